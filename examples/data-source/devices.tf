@@ -0,0 +1,21 @@
+data "livebox_devices" "nas" {
+  name = "nas"
+}
+
+resource "livebox_port_forwarding" "nas_https" {
+  name = "nas_https"
+  protocol = "tcp"
+  external_port = 443
+  internal_port = 443
+  destination = data.livebox_devices.nas.devices[0].ip_address
+  enabled = true
+}
+
+data "livebox_devices" "wifi" {
+  interface_type = "wifi_5ghz"
+  active = true
+}
+
+output "wifi_hosts" {
+  value = [for dev in data.livebox_devices.wifi.devices : dev.hostname]
+}
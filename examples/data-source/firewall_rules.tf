@@ -0,0 +1,8 @@
+data "livebox_firewall_rules" "inbound" {
+  direction = "inbound"
+  action = "accept"
+}
+
+output "accepted_ports" {
+  value = [for rule in data.livebox_firewall_rules.inbound.rules : "${rule.protocol}/${rule.destination_port}" if rule.destination_port != null]
+}
@@ -0,0 +1,7 @@
+data "livebox_port_forwardings" "enabled" {
+  enabled = true
+}
+
+output "exposed_ports" {
+  value = [for rule in data.livebox_port_forwardings.enabled.rules : "${rule.protocol}/${rule.external_port}"]
+}
@@ -0,0 +1,13 @@
+resource "livebox_dhcp_static_lease" "nas" {
+  mac_address = "aa:bb:cc:dd:ee:ff"
+  ip_address = "192.168.1.10"
+}
+
+resource "livebox_port_forwarding" "nas_https" {
+  name = "nas_https"
+  protocol = "tcp"
+  external_port = 443
+  internal_port = 443
+  destination = livebox_dhcp_static_lease.nas.ip_address
+  enabled = true
+}
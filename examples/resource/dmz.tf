@@ -0,0 +1,5 @@
+resource "livebox_dmz" "lab" {
+  destination = "192.168.1.50"
+  source = "198.51.100.0/24"
+  enabled = true
+}
@@ -0,0 +1,6 @@
+resource "livebox_firewall" "this" {
+  ipv4_level = "high"
+  ipv6_level = "medium"
+  respond_to_ping_ipv4 = false
+  respond_to_ping_ipv6 = true
+}
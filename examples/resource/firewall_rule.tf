@@ -0,0 +1,30 @@
+resource "livebox_firewall" "this" {
+  ipv4_level = "custom"
+  ipv6_level = "high"
+}
+
+resource "livebox_firewall_rule" "ssh" {
+  name = "ssh"
+  action = "accept"
+  protocol = "tcp"
+  source_prefix = "198.51.100.0/24"
+  destination_port = 22
+  enabled = true
+}
+
+resource "livebox_firewall_rule" "block_dns" {
+  name = "block_dns"
+  direction = "outbound"
+  action = "drop"
+  protocol = "tcp/udp"
+  destination_port = 53
+  enabled = true
+}
+
+resource "livebox_firewall_rule" "ping" {
+  name = "ping"
+  ip_version = 6
+  action = "accept"
+  protocol = "icmpv6"
+  enabled = true
+}
@@ -0,0 +1,24 @@
+resource "livebox_ipv6_pinhole" "https" {
+  name = "https"
+  protocol = "tcp"
+  destination_port = 443
+  destination = "2001:db8::10"
+  enabled = true
+}
+
+resource "livebox_ipv6_pinhole" "game" {
+  name = "game"
+  protocol = "udp"
+  destination_port = 27015
+  destination_port_end = 27030
+  destination = "2001:db8::20"
+  source = "2001:db8:ffff::/48"
+  enabled = true
+}
+
+resource "livebox_ipv6_pinhole" "ipsec" {
+  name = "ipsec"
+  protocol = "esp"
+  destination = "2001:db8::30"
+  enabled = true
+}
@@ -0,0 +1,8 @@
+resource "livebox_lan" "this" {
+  ip_address = "192.168.10.1"
+  netmask = "255.255.255.0"
+  dhcp_enabled = true
+  dhcp_min_address = "192.168.10.100"
+  dhcp_max_address = "192.168.10.199"
+  dhcp_lease_time = 43200
+}
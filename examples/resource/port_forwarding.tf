@@ -6,3 +6,12 @@ resource "livebox_port_forwarding" "wireguard" {
   destination = "192.168.10.200"
   enabled = true
 }
+
+resource "livebox_port_forwarding" "https" {
+  name = "https"
+  protocol = "tcp"
+  external_port = 443
+  internal_port = 443
+  destination = "aa:bb:cc:dd:ee:ff"
+  enabled = true
+}
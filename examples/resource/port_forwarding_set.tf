@@ -0,0 +1,20 @@
+# Every rule created from the web interface that is not declared below gets deleted.
+resource "livebox_port_forwarding_set" "all" {
+  rules = {
+    wireguard = {
+      protocol = "udp"
+      external_port = 51820
+      internal_port = 51820
+      destination = "192.168.10.200"
+      enabled = true
+    }
+    https = {
+      protocol = "tcp"
+      external_port = 443
+      internal_port = 443
+      destination = "192.168.10.201"
+      sources = ["203.0.113.0/24"]
+      enabled = true
+    }
+  }
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &devicesDataSource{}
+	_ datasource.DataSourceWithConfigure = &devicesDataSource{}
+)
+
+// devicesDataSource is the data source implementation.
+type devicesDataSource struct {
+	client *livebox.Client
+}
+
+// NewDevicesDataSource is a helper function to simplify the provider implementation.
+func NewDevicesDataSource() datasource.DataSource {
+	return &devicesDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *devicesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the data source type name.
+func (d *devicesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_devices"
+}
+
+// Schema defines the schema for the data source.
+func (d *devicesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the devices of the LAN known by a Livebox, whether they are currently connected or not.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "If set, only returns the devices with this name or hostname, compared case-insensitively.",
+			},
+			"interface_type": schema.StringAttribute{
+				Optional: true,
+				Description: `If set, only returns the devices connected with this kind of link. ` +
+					`Must be one of: "ethernet", "wifi_2.4ghz", "wifi_5ghz" or "wifi_6ghz".`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(livebox.InterfaceTypeEthernet, livebox.InterfaceTypeWiFi24, livebox.InterfaceTypeWiFi5, livebox.InterfaceTypeWiFi6),
+				},
+			},
+			"active": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If set, only returns the devices that are currently connected or disconnected.",
+			},
+			"devices": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Devices matching the filters, sorted by MAC address.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"mac_address": schema.StringAttribute{
+							Computed:    true,
+							Description: "MAC address of the device, in lower case.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the device, as displayed by the Livebox.",
+						},
+						"hostname": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name the device advertised through DHCP, empty if none.",
+						},
+						"ip_address": schema.StringAttribute{
+							Computed:    true,
+							Description: "Main IPv4 address of the device, empty if none.",
+						},
+						"ipv4_addresses": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "IPv4 addresses of the device.",
+						},
+						"ipv6_addresses": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "IPv6 addresses of the device.",
+						},
+						"interface_type": schema.StringAttribute{
+							Computed:    true,
+							Description: `Kind of link the device is connected with: "ethernet", "wifi_2.4ghz", "wifi_5ghz", "wifi_6ghz", or empty if unknown.`,
+						},
+						"active": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the device is currently connected or not.",
+						},
+						"last_seen": schema.StringAttribute{
+							Computed:    true,
+							Description: "Last time the device was connected to the Livebox, in RFC 3339 format, empty if unknown.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type devicesDataSourceModel struct {
+	Name          basetypes.StringValue          `tfsdk:"name"`
+	InterfaceType basetypes.StringValue          `tfsdk:"interface_type"`
+	Active        basetypes.BoolValue            `tfsdk:"active"`
+	Devices       []devicesDataSourceDeviceModel `tfsdk:"devices"`
+}
+
+type devicesDataSourceDeviceModel struct {
+	MACAddress    basetypes.StringValue `tfsdk:"mac_address"`
+	Name          basetypes.StringValue `tfsdk:"name"`
+	Hostname      basetypes.StringValue `tfsdk:"hostname"`
+	IPAddress     basetypes.StringValue `tfsdk:"ip_address"`
+	IPv4Addresses basetypes.ListValue   `tfsdk:"ipv4_addresses"`
+	IPv6Addresses basetypes.ListValue   `tfsdk:"ipv6_addresses"`
+	InterfaceType basetypes.StringValue `tfsdk:"interface_type"`
+	Active        basetypes.BoolValue   `tfsdk:"active"`
+	LastSeen      basetypes.StringValue `tfsdk:"last_seen"`
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *devicesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state devicesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	devices, err := d.client.ListDevices(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error listing devices",
+			fmt.Sprintf("Could not list devices: %v", err),
+		)
+		return
+	}
+
+	slices.SortFunc(devices, func(a, b livebox.Device) int {
+		return strings.Compare(a.MACAddress, b.MACAddress)
+	})
+
+	state.Devices = make([]devicesDataSourceDeviceModel, 0, len(devices))
+	for _, dev := range devices {
+		if !state.Name.IsNull() && !dev.MatchesName(state.Name.ValueString()) {
+			continue
+		}
+		if !state.InterfaceType.IsNull() && dev.InterfaceType != state.InterfaceType.ValueString() {
+			continue
+		}
+		if !state.Active.IsNull() && dev.Active != state.Active.ValueBool() {
+			continue
+		}
+
+		ipv4Addresses, diags := types.ListValueFrom(ctx, types.StringType, dev.IPv4Addresses)
+		resp.Diagnostics.Append(diags...)
+		ipv6Addresses, diags := types.ListValueFrom(ctx, types.StringType, dev.IPv6Addresses)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		var lastSeen string
+		if !dev.LastSeen.IsZero() {
+			lastSeen = dev.LastSeen.Format(time.RFC3339)
+		}
+
+		state.Devices = append(state.Devices, devicesDataSourceDeviceModel{
+			MACAddress:    types.StringValue(dev.MACAddress),
+			Name:          types.StringValue(dev.Name),
+			Hostname:      types.StringValue(dev.Hostname),
+			IPAddress:     types.StringValue(dev.IPAddress),
+			IPv4Addresses: ipv4Addresses,
+			IPv6Addresses: ipv6Addresses,
+			InterfaceType: types.StringValue(dev.InterfaceType),
+			Active:        types.BoolValue(dev.Active),
+			LastSeen:      types.StringValue(lastSeen),
+		})
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &dhcpStaticLeaseResource{}
+	_ resource.ResourceWithConfigure   = &dhcpStaticLeaseResource{}
+	_ resource.ResourceWithImportState = &dhcpStaticLeaseResource{}
+)
+
+// dhcpStaticLeaseResource is the resource implementation.
+type dhcpStaticLeaseResource struct {
+	client *livebox.Client
+}
+
+// NewDHCPStaticLeaseResource is a helper function to simplify the provider implementation.
+func NewDHCPStaticLeaseResource() resource.Resource {
+	return &dhcpStaticLeaseResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *dhcpStaticLeaseResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the resource type name.
+func (r *dhcpStaticLeaseResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dhcp_static_lease"
+}
+
+// Schema defines the schema for the resource.
+func (r *dhcpStaticLeaseResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reserve an IPv4 address of the DHCP pool of a Livebox for a device of the LAN.",
+		Attributes: map[string]schema.Attribute{
+			"mac_address": schema.StringAttribute{
+				Required:    true,
+				Description: "MAC address of the device to reserve an IP address for. Changing it replaces the lease.",
+				Validators: []validator.String{
+					macAddressValidator{},
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ip_address": schema.StringAttribute{
+				Required:    true,
+				Description: "IPv4 address reserved for the device. It must be inside the DHCP pool of the Livebox, which is checked when the lease is applied.",
+				Validators: []validator.String{
+					ipv4AddressValidator{},
+				},
+			},
+		},
+	}
+}
+
+type dhcpStaticLeaseModel struct {
+	MACAddress basetypes.StringValue `tfsdk:"mac_address"`
+	IPAddress  basetypes.StringValue `tfsdk:"ip_address"`
+}
+
+// staticLease returns the static lease described by the model.
+func (m *dhcpStaticLeaseModel) staticLease() livebox.StaticLease {
+	return livebox.StaticLease{
+		MACAddress: m.MACAddress.ValueString(),
+		IPAddress:  m.IPAddress.ValueString(),
+	}
+}
+
+// setStaticLease updates every attribute of the model with the values of the given static lease.
+func (m *dhcpStaticLeaseModel) setStaticLease(l *livebox.StaticLease) {
+	// The Livebox reports MAC addresses in lower case, keep the notation of the configuration if it is the same address.
+	if mac, err := net.ParseMAC(m.MACAddress.ValueString()); err != nil || mac.String() != l.MACAddress {
+		m.MACAddress = types.StringValue(l.MACAddress)
+	}
+	m.IPAddress = types.StringValue(l.IPAddress)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *dhcpStaticLeaseResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan dhcpStaticLeaseModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	lease := plan.staticLease()
+	err := r.client.UpsertStaticLease(ctx, lease)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating DHCP static lease",
+			fmt.Sprintf("Could not create static lease for %q, unexpected error: %v", lease.MACAddress, err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *dhcpStaticLeaseResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state dhcpStaticLeaseModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mac := state.MACAddress.ValueString()
+	lease, err := r.client.GetStaticLease(ctx, mac)
+	if errors.Is(err, livebox.ErrNotFound) {
+		// The lease has been deleted outside of Terraform, remove it from the state so it gets recreated.
+		tflog.Warn(ctx, "DHCP static lease not found, removing it from state", map[string]any{"mac_address": mac})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading DHCP static lease",
+			fmt.Sprintf("Could not read state for static lease %q: %v", mac, err),
+		)
+		return
+	}
+
+	state.setStaticLease(lease)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *dhcpStaticLeaseResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan dhcpStaticLeaseModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	lease := plan.staticLease()
+	err := r.client.UpsertStaticLease(ctx, lease)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating DHCP static lease",
+			fmt.Sprintf("Could not update static lease for %q: %v", lease.MACAddress, err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *dhcpStaticLeaseResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state dhcpStaticLeaseModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mac := state.MACAddress.ValueString()
+	err := r.client.DeleteStaticLease(ctx, mac)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting DHCP static lease",
+			fmt.Sprintf("Could not delete static lease %q: %v", mac, err),
+		)
+		return
+	}
+}
+
+// ImportState imports an existing static lease into Terraform, using the MAC address of the device as import ID.
+func (r *dhcpStaticLeaseResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	lease, err := r.client.GetStaticLease(ctx, req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing DHCP static lease",
+			fmt.Sprintf("Could not import static lease %q: %v", req.ID, err),
+		)
+		return
+	}
+
+	var state dhcpStaticLeaseModel
+	state.setStaticLease(lease)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
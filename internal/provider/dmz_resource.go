@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &dmzResource{}
+	_ resource.ResourceWithConfigure   = &dmzResource{}
+	_ resource.ResourceWithImportState = &dmzResource{}
+)
+
+// dmzResource is the resource implementation.
+type dmzResource struct {
+	client *livebox.Client
+}
+
+// NewDMZResource is a helper function to simplify the provider implementation.
+func NewDMZResource() resource.Resource {
+	return &dmzResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *dmzResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the resource type name.
+func (r *dmzResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dmz"
+}
+
+// Schema defines the schema for the resource.
+func (r *dmzResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Configure the DMZ host of a Livebox, to which all unsolicited inbound IPv4 traffic " +
+			"that does not match a port forwarding rule is forwarded. A Livebox supports a single DMZ host.",
+		Attributes: map[string]schema.Attribute{
+			"destination": schema.StringAttribute{
+				Required:    true,
+				Description: "IPv4 address of the DMZ host.",
+				Validators: []validator.String{
+					ipv4AddressValidator{},
+				},
+			},
+			"source": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: `IP address or IPv4 CIDR prefix (e.g. "192.0.2.0/24") allowed to reach the DMZ host. If empty, traffic from any address is forwarded.`,
+				Validators: []validator.String{
+					sourcePrefixValidator{},
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Required:    true,
+				Description: "Whether the DMZ is enabled or not.",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: `Status of the DMZ as reported by the Livebox (e.g. "Enabled", "Disabled" or "Error").`,
+			},
+		},
+	}
+}
+
+type dmzModel struct {
+	Destination basetypes.StringValue `tfsdk:"destination"`
+	Source      basetypes.StringValue `tfsdk:"source"`
+	Enabled     basetypes.BoolValue   `tfsdk:"enabled"`
+	Status      basetypes.StringValue `tfsdk:"status"`
+}
+
+// config returns the configuration of the DMZ described by the model.
+func (m *dmzModel) config() livebox.DMZConfig {
+	return livebox.DMZConfig{
+		Destination: m.Destination.ValueString(),
+		Source:      m.Source.ValueString(),
+		Enabled:     m.Enabled.ValueBool(),
+	}
+}
+
+// setDMZ updates every attribute of the model with the values of the given DMZ.
+func (m *dmzModel) setDMZ(dmz *livebox.DMZ) {
+	m.Enabled = types.BoolValue(dmz.Enabled)
+	m.Status = types.StringValue(dmz.Status)
+
+	// Only overwrite addresses if they actually changed, so that a different notation
+	// in the configuration does not produce a diff.
+	if m.Destination.IsNull() || canonicalAddress(m.Destination.ValueString()) != canonicalAddress(dmz.Destination) {
+		m.Destination = types.StringValue(dmz.Destination)
+	}
+	if m.Source.IsNull() || canonicalAddress(m.Source.ValueString()) != canonicalAddress(dmz.Source) {
+		m.Source = types.StringValue(dmz.Source)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *dmzResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan dmzModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetDMZ(ctx, plan.config())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating DMZ",
+			fmt.Sprintf("Could not create DMZ, unexpected error: %v", err),
+		)
+		return
+	}
+
+	dmz, err := r.client.GetDMZ(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating DMZ",
+			fmt.Sprintf("Could not read DMZ after creating it: %v", err),
+		)
+		return
+	}
+
+	plan.Status = types.StringValue(dmz.Status)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *dmzResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state dmzModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dmz, err := r.client.GetDMZ(ctx)
+	if errors.Is(err, livebox.ErrNotFound) {
+		// The DMZ has been removed outside of Terraform, remove it from the state so it gets recreated.
+		tflog.Warn(ctx, "DMZ not found, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading DMZ",
+			fmt.Sprintf("Could not read state for DMZ: %v", err),
+		)
+		return
+	}
+
+	state.setDMZ(dmz)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *dmzResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan dmzModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetDMZ(ctx, plan.config())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating DMZ",
+			fmt.Sprintf("Could not update DMZ: %v", err),
+		)
+		return
+	}
+
+	dmz, err := r.client.GetDMZ(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating DMZ",
+			fmt.Sprintf("Could not read DMZ after updating it: %v", err),
+		)
+		return
+	}
+
+	plan.Status = types.StringValue(dmz.Status)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *dmzResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	err := r.client.DeleteDMZ(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting DMZ",
+			fmt.Sprintf("Could not delete DMZ: %v", err),
+		)
+		return
+	}
+}
+
+// ImportState imports the DMZ currently configured on the Livebox into Terraform.
+// Since a Livebox supports a single DMZ host, the import ID must be its fixed ID, "webui".
+func (r *dmzResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID != livebox.DMZID {
+		resp.Diagnostics.AddError(
+			"Error importing DMZ",
+			fmt.Sprintf("Could not import DMZ %q: the import ID must be %q", req.ID, livebox.DMZID),
+		)
+		return
+	}
+
+	dmz, err := r.client.GetDMZ(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing DMZ",
+			fmt.Sprintf("Could not import DMZ: %v", err),
+		)
+		return
+	}
+
+	var state dmzModel
+	state.setDMZ(dmz)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
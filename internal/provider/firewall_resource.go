@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &firewallResource{}
+	_ resource.ResourceWithConfigure   = &firewallResource{}
+	_ resource.ResourceWithImportState = &firewallResource{}
+)
+
+// firewallLevels lists the values accepted for the firewall levels.
+var firewallLevels = []string{
+	string(livebox.FirewallLevelLow),
+	string(livebox.FirewallLevelMedium),
+	string(livebox.FirewallLevelHigh),
+	string(livebox.FirewallLevelCustom),
+}
+
+// firewallResource is the resource implementation.
+type firewallResource struct {
+	client *livebox.Client
+}
+
+// NewFirewallResource is a helper function to simplify the provider implementation.
+func NewFirewallResource() resource.Resource {
+	return &firewallResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *firewallResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the resource type name.
+func (r *firewallResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall"
+}
+
+// Schema defines the schema for the resource.
+func (r *firewallResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Configure the global settings of the firewall of a Livebox. A Livebox has a single firewall, " +
+			"so this resource should be declared at most once. Destroying it leaves the settings of the Livebox unchanged.",
+		Attributes: map[string]schema.Attribute{
+			"ipv4_level": schema.StringAttribute{
+				Required:    true,
+				Description: `Level of the IPv4 firewall. Must be one of: "low", "medium", "high" or "custom".`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(firewallLevels...),
+				},
+			},
+			"ipv6_level": schema.StringAttribute{
+				Required:    true,
+				Description: `Level of the IPv6 firewall. Must be one of: "low", "medium", "high" or "custom".`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(firewallLevels...),
+				},
+			},
+			"respond_to_ping_ipv4": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether the Livebox responds to IPv4 pings from the Internet. Defaults to false.",
+			},
+			"respond_to_ping_ipv6": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether the Livebox responds to IPv6 pings from the Internet. Defaults to false.",
+			},
+		},
+	}
+}
+
+type firewallModel struct {
+	IPv4Level         basetypes.StringValue `tfsdk:"ipv4_level"`
+	IPv6Level         basetypes.StringValue `tfsdk:"ipv6_level"`
+	RespondToPingIPv4 basetypes.BoolValue   `tfsdk:"respond_to_ping_ipv4"`
+	RespondToPingIPv6 basetypes.BoolValue   `tfsdk:"respond_to_ping_ipv6"`
+}
+
+// settings returns the firewall settings described by the model.
+func (m *firewallModel) settings() livebox.FirewallSettings {
+	return livebox.FirewallSettings{
+		IPv4Level:         livebox.FirewallLevel(m.IPv4Level.ValueString()),
+		IPv6Level:         livebox.FirewallLevel(m.IPv6Level.ValueString()),
+		RespondToPingIPv4: m.RespondToPingIPv4.ValueBool(),
+		RespondToPingIPv6: m.RespondToPingIPv6.ValueBool(),
+	}
+}
+
+// setSettings updates every attribute of the model with the given firewall settings.
+func (m *firewallModel) setSettings(s *livebox.FirewallSettings) {
+	m.IPv4Level = types.StringValue(string(s.IPv4Level))
+	m.IPv6Level = types.StringValue(string(s.IPv6Level))
+	m.RespondToPingIPv4 = types.BoolValue(s.RespondToPingIPv4)
+	m.RespondToPingIPv6 = types.BoolValue(s.RespondToPingIPv6)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *firewallResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan firewallModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetFirewallSettings(ctx, plan.settings())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error configuring firewall",
+			fmt.Sprintf("Could not configure firewall, unexpected error: %v", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *firewallResource) Read(ctx context.Context, _ resource.ReadRequest, resp *resource.ReadResponse) {
+	settings, err := r.client.GetFirewallSettings(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading firewall",
+			fmt.Sprintf("Could not read firewall settings: %v", err),
+		)
+		return
+	}
+
+	var state firewallModel
+	state.setSettings(settings)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *firewallResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan firewallModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetFirewallSettings(ctx, plan.settings())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating firewall",
+			fmt.Sprintf("Could not update firewall settings: %v", err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the resource from the Terraform state. The firewall of a Livebox
+// cannot be removed, so its settings are left as they are.
+func (r *firewallResource) Delete(ctx context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	tflog.Info(ctx, "Removing firewall from state, its settings are left unchanged on the Livebox")
+}
+
+// ImportState imports the current firewall settings of the Livebox into Terraform.
+// Since a Livebox has a single firewall, the import ID is ignored.
+func (r *firewallResource) ImportState(ctx context.Context, _ resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	settings, err := r.client.GetFirewallSettings(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing firewall",
+			fmt.Sprintf("Could not import firewall settings: %v", err),
+		)
+		return
+	}
+
+	var state firewallModel
+	state.setSettings(settings)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
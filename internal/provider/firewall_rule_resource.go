@@ -0,0 +1,473 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &firewallRuleResource{}
+	_ resource.ResourceWithConfigure      = &firewallRuleResource{}
+	_ resource.ResourceWithImportState    = &firewallRuleResource{}
+	_ resource.ResourceWithValidateConfig = &firewallRuleResource{}
+)
+
+// firewallRuleResource is the resource implementation.
+type firewallRuleResource struct {
+	client *livebox.Client
+}
+
+// NewFirewallRuleResource is a helper function to simplify the provider implementation.
+func NewFirewallRuleResource() resource.Resource {
+	return &firewallRuleResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *firewallRuleResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the resource type name.
+func (r *firewallRuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_rule"
+}
+
+// Schema defines the schema for the resource.
+func (r *firewallRuleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: `Configure a custom firewall rule on a Livebox. Custom rules are only applied when the level of the firewall is "custom".`,
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Arbitrary name of the rule, unique among the rules of the same direction and IP version. Changing it replaces the rule.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"direction": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(string(livebox.FirewallRuleDirectionInbound)),
+				Description: `Direction of the traffic the rule applies to. Must be one of: "inbound" or "outbound". Defaults to "inbound". Changing it replaces the rule.`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(livebox.FirewallRuleDirectionInbound), string(livebox.FirewallRuleDirectionOutbound)),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ip_version": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(4),
+				Description: "IP version of the traffic the rule applies to. Must be 4 or 6. Defaults to 4. Changing it replaces the rule.",
+				Validators: []validator.Int64{
+					int64validator.OneOf(4, 6),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"action": schema.StringAttribute{
+				Required:    true,
+				Description: `What to do with the traffic matching the rule. Must be one of: "accept" or "drop".`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(livebox.FirewallRuleActionAccept), string(livebox.FirewallRuleActionDrop)),
+				},
+			},
+			"protocol": schema.StringAttribute{
+				Required:    true,
+				Description: `Protocol of the traffic matching the rule. Must be one of: "tcp", "udp", "tcp/udp", "icmp", "icmpv6", "gre", "esp", "ah" or the number of another IP protocol (e.g. "132"). Ports can only be set for "tcp", "udp" and "tcp/udp".`,
+				Validators: []validator.String{
+					protocolValidator{},
+				},
+			},
+			"source_prefix": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Source address or CIDR prefix of the traffic matching the rule, of the same IP version as the rule. If empty, traffic from any address matches.",
+			},
+			"source_port": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Source port of the traffic matching the rule. If not set, traffic from any port matches.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"source_port_end": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "If set, matches the range of consecutive source ports from source_port to this port (inclusive). Defaults to source_port.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+					int64validator.AlsoRequires(path.MatchRoot("source_port")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultFromAttribute{path: path.Root("source_port")},
+				},
+			},
+			"destination_prefix": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Destination address or CIDR prefix of the traffic matching the rule, of the same IP version as the rule. If empty, traffic to any address matches.",
+			},
+			"destination_port": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Destination port of the traffic matching the rule. If not set, traffic to any port matches.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"destination_port_end": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "If set, matches the range of consecutive destination ports from destination_port to this port (inclusive). Defaults to destination_port.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+					int64validator.AlsoRequires(path.MatchRoot("destination_port")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultFromAttribute{path: path.Root("destination_port")},
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Required:    true,
+				Description: "Whether this rule is enabled or not.",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: `Status of the rule as reported by the Livebox (e.g. "Enabled", "Disabled" or "Error").`,
+			},
+		},
+	}
+}
+
+type firewallRuleModel struct {
+	Name               basetypes.StringValue `tfsdk:"name"`
+	Direction          basetypes.StringValue `tfsdk:"direction"`
+	IPVersion          basetypes.Int64Value  `tfsdk:"ip_version"`
+	Action             basetypes.StringValue `tfsdk:"action"`
+	Protocol           basetypes.StringValue `tfsdk:"protocol"`
+	SourcePrefix       basetypes.StringValue `tfsdk:"source_prefix"`
+	SourcePort         basetypes.Int64Value  `tfsdk:"source_port"`
+	SourcePortEnd      basetypes.Int64Value  `tfsdk:"source_port_end"`
+	DestinationPrefix  basetypes.StringValue `tfsdk:"destination_prefix"`
+	DestinationPort    basetypes.Int64Value  `tfsdk:"destination_port"`
+	DestinationPortEnd basetypes.Int64Value  `tfsdk:"destination_port_end"`
+	Enabled            basetypes.BoolValue   `tfsdk:"enabled"`
+	Status             basetypes.StringValue `tfsdk:"status"`
+}
+
+// config returns the configuration of the firewall rule described by the model.
+func (m *firewallRuleModel) config() livebox.FirewallRuleConfig {
+	return livebox.FirewallRuleConfig{
+		Name:               m.Name.ValueString(),
+		Action:             livebox.FirewallRuleAction(m.Action.ValueString()),
+		Direction:          livebox.FirewallRuleDirection(m.Direction.ValueString()),
+		Protocol:           livebox.Protocol(m.Protocol.ValueString()),
+		IPVersion:          int(m.IPVersion.ValueInt64()),
+		SourcePrefix:       m.SourcePrefix.ValueString(),
+		SourcePort:         int(m.SourcePort.ValueInt64()),
+		SourcePortEnd:      int(m.SourcePortEnd.ValueInt64()),
+		DestinationPrefix:  m.DestinationPrefix.ValueString(),
+		DestinationPort:    int(m.DestinationPort.ValueInt64()),
+		DestinationPortEnd: int(m.DestinationPortEnd.ValueInt64()),
+		Enabled:            m.Enabled.ValueBool(),
+	}
+}
+
+// setFirewallRule updates every attribute of the model with the values of the given rule.
+func (m *firewallRuleModel) setFirewallRule(rule *livebox.FirewallRule) {
+	m.Name = types.StringValue(rule.Name)
+	m.Direction = types.StringValue(string(rule.Direction))
+	m.IPVersion = types.Int64Value(int64(rule.IPVersion))
+	m.Action = types.StringValue(string(rule.Action))
+	m.Protocol = types.StringValue(string(rule.Protocol))
+	m.SourcePort, m.SourcePortEnd = optionalPortRange(rule.SourcePort, rule.SourcePortEnd)
+	m.DestinationPort, m.DestinationPortEnd = optionalPortRange(rule.DestinationPort, rule.DestinationPortEnd)
+	m.Enabled = types.BoolValue(rule.Enabled)
+	m.Status = types.StringValue(rule.Status)
+
+	// Only overwrite prefixes if they actually changed, so that a different notation
+	// in the configuration does not produce a diff.
+	if m.SourcePrefix.IsNull() || canonicalAddress(m.SourcePrefix.ValueString()) != canonicalAddress(rule.SourcePrefix) {
+		m.SourcePrefix = types.StringValue(rule.SourcePrefix)
+	}
+	if m.DestinationPrefix.IsNull() || canonicalAddress(m.DestinationPrefix.ValueString()) != canonicalAddress(rule.DestinationPrefix) {
+		m.DestinationPrefix = types.StringValue(rule.DestinationPrefix)
+	}
+}
+
+// optionalPortRange returns the attribute values of a port range where a start port of 0 means any port.
+func optionalPortRange(start, end int) (basetypes.Int64Value, basetypes.Int64Value) {
+	if start == 0 {
+		return types.Int64Null(), types.Int64Null()
+	}
+
+	return types.Int64Value(int64(start)), types.Int64Value(int64(end))
+}
+
+// ValidateConfig validates the attributes of the configuration which depend on each other.
+func (r *firewallRuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cfg firewallRuleModel
+	diags := req.Config.Get(ctx, &cfg)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !cfg.IPVersion.IsUnknown() {
+		ipVersion := cfg.IPVersion.ValueInt64()
+		if cfg.IPVersion.IsNull() {
+			ipVersion = 4
+		}
+
+		for name, prefix := range map[string]basetypes.StringValue{
+			"source_prefix":      cfg.SourcePrefix,
+			"destination_prefix": cfg.DestinationPrefix,
+		} {
+			if prefix.IsNull() || prefix.IsUnknown() || prefix.ValueString() == "" {
+				continue
+			}
+
+			if !livebox.IsValidPrefix(prefix.ValueString(), int(ipVersion)) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(name),
+					"Invalid Prefix",
+					fmt.Sprintf("Attribute %s must be a valid IPv%d address or CIDR prefix, got: %q", name, ipVersion, prefix.ValueString()),
+				)
+			}
+		}
+	}
+
+	for _, ports := range []struct {
+		name       string
+		start, end basetypes.Int64Value
+	}{
+		{"source_port", cfg.SourcePort, cfg.SourcePortEnd},
+		{"destination_port", cfg.DestinationPort, cfg.DestinationPortEnd},
+	} {
+		if !ports.start.IsNull() && !cfg.Protocol.IsNull() && !cfg.Protocol.IsUnknown() &&
+			!livebox.Protocol(cfg.Protocol.ValueString()).HasPorts() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(ports.name),
+				"Invalid Port",
+				fmt.Sprintf("Attribute %s can only be set for the \"tcp\", \"udp\" and \"tcp/udp\" protocols, got protocol: %q", ports.name, cfg.Protocol.ValueString()),
+			)
+		}
+
+		if ports.start.IsNull() || ports.start.IsUnknown() || ports.end.IsNull() || ports.end.IsUnknown() {
+			continue
+		}
+
+		if ports.end.ValueInt64() < ports.start.ValueInt64() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(ports.name+"_end"),
+				"Invalid Port Range",
+				fmt.Sprintf("Attribute %s_end must be greater than or equal to %s, got: %d", ports.name, ports.name, ports.end.ValueInt64()),
+			)
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *firewallRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan firewallRuleModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg := plan.config()
+	err := r.client.UpsertFirewallRule(ctx, cfg)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating firewall rule",
+			fmt.Sprintf("Could not create firewall rule %q, unexpected error: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	rule, err := r.client.GetFirewallRule(ctx, cfg.Direction, cfg.IPVersion, cfg.Name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating firewall rule",
+			fmt.Sprintf("Could not read firewall rule %q after creating it: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	plan.Status = types.StringValue(rule.Status)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *firewallRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state firewallRuleModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg := state.config()
+	rule, err := r.client.GetFirewallRule(ctx, cfg.Direction, cfg.IPVersion, cfg.Name)
+	if errors.Is(err, livebox.ErrNotFound) {
+		// The rule has been deleted outside of Terraform, remove it from the state so it gets recreated.
+		tflog.Warn(ctx, "Firewall rule not found, removing it from state", map[string]any{
+			"direction":  cfg.Direction,
+			"ip_version": cfg.IPVersion,
+			"name":       cfg.Name,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading firewall rule",
+			fmt.Sprintf("Could not read state for firewall rule %q: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	state.setFirewallRule(rule)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *firewallRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan firewallRuleModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg := plan.config()
+	err := r.client.UpsertFirewallRule(ctx, cfg)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating firewall rule",
+			fmt.Sprintf("Could not update firewall rule %q: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	rule, err := r.client.GetFirewallRule(ctx, cfg.Direction, cfg.IPVersion, cfg.Name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating firewall rule",
+			fmt.Sprintf("Could not read firewall rule %q after updating it: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	plan.Status = types.StringValue(rule.Status)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *firewallRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state firewallRuleModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg := state.config()
+	err := r.client.DeleteFirewallRule(ctx, cfg.Direction, cfg.IPVersion, cfg.Name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting firewall rule",
+			fmt.Sprintf("Could not delete firewall rule %q: %v", cfg.Name, err),
+		)
+		return
+	}
+}
+
+// ImportState imports an existing custom firewall rule into Terraform.
+// The import ID is made of the direction, IP version and name of the rule, separated by slashes
+// (e.g. "inbound/4/ssh"). If the name is unique among all the custom rules, it can be used alone.
+func (r *firewallRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	rules, err := r.client.ListFirewallRules(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing firewall rule",
+			fmt.Sprintf("Could not import firewall rule %q: %v", req.ID, err),
+		)
+		return
+	}
+
+	var matches []livebox.FirewallRule
+	for _, rule := range rules {
+		if req.ID == rule.Name || req.ID == firewallRuleImportID(rule) {
+			matches = append(matches, rule)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		resp.Diagnostics.AddError(
+			"Error importing firewall rule",
+			fmt.Sprintf("Could not import firewall rule %q: no rule with this ID or name", req.ID),
+		)
+		return
+	case 1:
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, rule := range matches {
+			ids = append(ids, firewallRuleImportID(rule))
+		}
+
+		resp.Diagnostics.AddError(
+			"Error importing firewall rule",
+			fmt.Sprintf("Could not import firewall rule %q: several rules have this name, use one of: %s", req.ID, strings.Join(ids, ", ")),
+		)
+		return
+	}
+
+	var state firewallRuleModel
+	state.setFirewallRule(&matches[0])
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// firewallRuleImportID returns the import ID of the given rule.
+func firewallRuleImportID(rule livebox.FirewallRule) string {
+	return string(rule.Direction) + "/" + strconv.Itoa(rule.IPVersion) + "/" + rule.Name
+}
@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &firewallRulesDataSource{}
+	_ datasource.DataSourceWithConfigure = &firewallRulesDataSource{}
+)
+
+// firewallRulesDataSource is the data source implementation.
+type firewallRulesDataSource struct {
+	client *livebox.Client
+}
+
+// NewFirewallRulesDataSource is a helper function to simplify the provider implementation.
+func NewFirewallRulesDataSource() datasource.DataSource {
+	return &firewallRulesDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *firewallRulesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the data source type name.
+func (d *firewallRulesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_rules"
+}
+
+// Schema defines the schema for the data source.
+func (d *firewallRulesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the custom firewall rules configured on a Livebox.",
+		Attributes: map[string]schema.Attribute{
+			"direction": schema.StringAttribute{
+				Optional:    true,
+				Description: `If set, only returns the rules applying to this direction. Must be one of: "inbound" or "outbound".`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(livebox.FirewallRuleDirectionInbound), string(livebox.FirewallRuleDirectionOutbound)),
+				},
+			},
+			"ip_version": schema.Int64Attribute{
+				Optional:    true,
+				Description: "If set, only returns the rules applying to this IP version. Must be 4 or 6.",
+				Validators: []validator.Int64{
+					int64validator.OneOf(4, 6),
+				},
+			},
+			"action": schema.StringAttribute{
+				Optional:    true,
+				Description: `If set, only returns the rules with this action. Must be one of: "accept" or "drop".`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(livebox.FirewallRuleActionAccept), string(livebox.FirewallRuleActionDrop)),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If set, only returns the rules that are enabled or disabled.",
+			},
+			"rules": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Custom firewall rules matching the filters, sorted by direction, IP version and name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the rule.",
+						},
+						"direction": schema.StringAttribute{
+							Computed:    true,
+							Description: "Direction of the traffic the rule applies to.",
+						},
+						"ip_version": schema.Int64Attribute{
+							Computed:    true,
+							Description: "IP version of the traffic the rule applies to.",
+						},
+						"action": schema.StringAttribute{
+							Computed:    true,
+							Description: "What is done with the traffic matching the rule.",
+						},
+						"protocol": schema.StringAttribute{
+							Computed:    true,
+							Description: "Protocol of the traffic matching the rule.",
+						},
+						"source_prefix": schema.StringAttribute{
+							Computed:    true,
+							Description: "Source address or CIDR prefix of the traffic matching the rule, empty if any address matches.",
+						},
+						"source_port": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Source port of the traffic matching the rule, null if any port matches.",
+						},
+						"source_port_end": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Last source port of the range matched, equal to source_port when a single port is matched.",
+						},
+						"destination_prefix": schema.StringAttribute{
+							Computed:    true,
+							Description: "Destination address or CIDR prefix of the traffic matching the rule, empty if any address matches.",
+						},
+						"destination_port": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Destination port of the traffic matching the rule, null if any port matches.",
+						},
+						"destination_port_end": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Last destination port of the range matched, equal to destination_port when a single port is matched.",
+						},
+						"enabled": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether this rule is enabled or not.",
+						},
+						"status": schema.StringAttribute{
+							Computed:    true,
+							Description: "Status of the rule as reported by the Livebox.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type firewallRulesDataSourceModel struct {
+	Direction basetypes.StringValue              `tfsdk:"direction"`
+	IPVersion basetypes.Int64Value               `tfsdk:"ip_version"`
+	Action    basetypes.StringValue              `tfsdk:"action"`
+	Enabled   basetypes.BoolValue                `tfsdk:"enabled"`
+	Rules     []firewallRulesDataSourceRuleModel `tfsdk:"rules"`
+}
+
+type firewallRulesDataSourceRuleModel struct {
+	Name               basetypes.StringValue `tfsdk:"name"`
+	Direction          basetypes.StringValue `tfsdk:"direction"`
+	IPVersion          basetypes.Int64Value  `tfsdk:"ip_version"`
+	Action             basetypes.StringValue `tfsdk:"action"`
+	Protocol           basetypes.StringValue `tfsdk:"protocol"`
+	SourcePrefix       basetypes.StringValue `tfsdk:"source_prefix"`
+	SourcePort         basetypes.Int64Value  `tfsdk:"source_port"`
+	SourcePortEnd      basetypes.Int64Value  `tfsdk:"source_port_end"`
+	DestinationPrefix  basetypes.StringValue `tfsdk:"destination_prefix"`
+	DestinationPort    basetypes.Int64Value  `tfsdk:"destination_port"`
+	DestinationPortEnd basetypes.Int64Value  `tfsdk:"destination_port_end"`
+	Enabled            basetypes.BoolValue   `tfsdk:"enabled"`
+	Status             basetypes.StringValue `tfsdk:"status"`
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *firewallRulesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state firewallRulesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, err := d.client.ListFirewallRules(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error listing firewall rules",
+			fmt.Sprintf("Could not list firewall rules: %v", err),
+		)
+		return
+	}
+
+	slices.SortFunc(rules, func(a, b livebox.FirewallRule) int {
+		return strings.Compare(firewallRuleImportID(a), firewallRuleImportID(b))
+	})
+
+	state.Rules = make([]firewallRulesDataSourceRuleModel, 0, len(rules))
+	for _, rule := range rules {
+		if !state.Direction.IsNull() && string(rule.Direction) != state.Direction.ValueString() {
+			continue
+		}
+		if !state.IPVersion.IsNull() && int64(rule.IPVersion) != state.IPVersion.ValueInt64() {
+			continue
+		}
+		if !state.Action.IsNull() && string(rule.Action) != state.Action.ValueString() {
+			continue
+		}
+		if !state.Enabled.IsNull() && rule.Enabled != state.Enabled.ValueBool() {
+			continue
+		}
+
+		m := firewallRulesDataSourceRuleModel{
+			Name:              types.StringValue(rule.Name),
+			Direction:         types.StringValue(string(rule.Direction)),
+			IPVersion:         types.Int64Value(int64(rule.IPVersion)),
+			Action:            types.StringValue(string(rule.Action)),
+			Protocol:          types.StringValue(string(rule.Protocol)),
+			SourcePrefix:      types.StringValue(rule.SourcePrefix),
+			DestinationPrefix: types.StringValue(rule.DestinationPrefix),
+			Enabled:           types.BoolValue(rule.Enabled),
+			Status:            types.StringValue(rule.Status),
+		}
+		m.SourcePort, m.SourcePortEnd = optionalPortRange(rule.SourcePort, rule.SourcePortEnd)
+		m.DestinationPort, m.DestinationPortEnd = optionalPortRange(rule.DestinationPort, rule.DestinationPortEnd)
+
+		state.Rules = append(state.Rules, m)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
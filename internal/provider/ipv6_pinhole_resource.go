@@ -0,0 +1,363 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ipv6PinholeResource{}
+	_ resource.ResourceWithConfigure      = &ipv6PinholeResource{}
+	_ resource.ResourceWithImportState    = &ipv6PinholeResource{}
+	_ resource.ResourceWithValidateConfig = &ipv6PinholeResource{}
+)
+
+// ipv6PinholeResource is the resource implementation.
+type ipv6PinholeResource struct {
+	client *livebox.Client
+}
+
+// NewIPv6PinholeResource is a helper function to simplify the provider implementation.
+func NewIPv6PinholeResource() resource.Resource {
+	return &ipv6PinholeResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ipv6PinholeResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the resource type name.
+func (r *ipv6PinholeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ipv6_pinhole"
+}
+
+// Schema defines the schema for the resource.
+func (r *ipv6PinholeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Configure an IPv6 pinhole on a Livebox, allowing inbound IPv6 traffic to reach a host of the LAN.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Arbitrary but unique name of the pinhole to create. Changing it replaces the pinhole.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"origin": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(livebox.OriginWebUI),
+				Description: `Origin of the pinhole (e.g. "webui" or "upnp"). Defaults to "webui". Changing it replaces the pinhole.`,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"protocol": schema.StringAttribute{
+				Required:    true,
+				Description: `Protocol of the pinhole to create. Must be one of: "tcp", "udp", "tcp/udp", "icmp", "icmpv6", "gre", "esp", "ah" or the number of another IP protocol (e.g. "132"). Ports can only be set for "tcp", "udp" and "tcp/udp".`,
+				Validators: []validator.String{
+					protocolValidator{},
+				},
+			},
+			"destination_port": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Destination port opened by the pinhole. If not set, traffic to any port is allowed.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"destination_port_end": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "If set, opens the range of consecutive ports from destination_port to this port (inclusive). Defaults to destination_port.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+					int64validator.AlsoRequires(path.MatchRoot("destination_port")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultFromAttribute{path: path.Root("destination_port")},
+				},
+			},
+			"destination": schema.StringAttribute{
+				Required:    true,
+				Description: "IPv6 address of the host to allow traffic to.",
+				Validators: []validator.String{
+					ipv6AddressValidator{},
+				},
+			},
+			"source": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "IPv6 address or prefix allowed to reach the destination. If empty, traffic from any address is allowed.",
+				Validators: []validator.String{
+					ipv6PrefixValidator{},
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Required:    true,
+				Description: "Whether this pinhole is enabled or not.",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: `Status of the pinhole as reported by the Livebox (e.g. "Enabled", "Disabled" or "Error").`,
+			},
+		},
+	}
+}
+
+type ipv6PinholeModel struct {
+	Name               basetypes.StringValue `tfsdk:"name"`
+	Origin             basetypes.StringValue `tfsdk:"origin"`
+	Protocol           basetypes.StringValue `tfsdk:"protocol"`
+	DestinationPort    basetypes.Int64Value  `tfsdk:"destination_port"`
+	DestinationPortEnd basetypes.Int64Value  `tfsdk:"destination_port_end"`
+	Destination        basetypes.StringValue `tfsdk:"destination"`
+	Source             basetypes.StringValue `tfsdk:"source"`
+	Enabled            basetypes.BoolValue   `tfsdk:"enabled"`
+	Status             basetypes.StringValue `tfsdk:"status"`
+}
+
+// config returns the configuration of the pinhole described by the model.
+func (m *ipv6PinholeModel) config() livebox.PinholeConfig {
+	return livebox.PinholeConfig{
+		Name:               m.Name.ValueString(),
+		Origin:             m.Origin.ValueString(),
+		Protocol:           livebox.Protocol(m.Protocol.ValueString()),
+		DestinationPort:    int(m.DestinationPort.ValueInt64()),
+		DestinationPortEnd: int(m.DestinationPortEnd.ValueInt64()),
+		Destination:        m.Destination.ValueString(),
+		Source:             m.Source.ValueString(),
+		Enabled:            m.Enabled.ValueBool(),
+	}
+}
+
+// setPinhole updates every attribute of the model with the values of the given pinhole.
+func (m *ipv6PinholeModel) setPinhole(ph *livebox.Pinhole) {
+	m.Name = types.StringValue(ph.Name)
+	m.Origin = types.StringValue(ph.Origin)
+	m.Protocol = types.StringValue(string(ph.Protocol))
+	m.DestinationPort, m.DestinationPortEnd = optionalPortRange(ph.DestinationPort, ph.DestinationPortEnd)
+	m.Enabled = types.BoolValue(ph.Enabled)
+	m.Status = types.StringValue(ph.Status)
+
+	// Only overwrite addresses if they actually changed, so that a different notation
+	// in the configuration (e.g. upper case letters) does not produce a diff.
+	if m.Destination.IsNull() || canonicalAddress(m.Destination.ValueString()) != canonicalAddress(ph.Destination) {
+		m.Destination = types.StringValue(ph.Destination)
+	}
+	if m.Source.IsNull() || canonicalAddress(m.Source.ValueString()) != canonicalAddress(ph.Source) {
+		m.Source = types.StringValue(ph.Source)
+	}
+}
+
+// ValidateConfig validates that ports are only set for protocols using them, and that the port range is not reversed.
+func (r *ipv6PinholeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cfg ipv6PinholeModel
+	diags := req.Config.Get(ctx, &cfg)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !cfg.DestinationPort.IsNull() && !cfg.Protocol.IsNull() && !cfg.Protocol.IsUnknown() &&
+		!livebox.Protocol(cfg.Protocol.ValueString()).HasPorts() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("destination_port"),
+			"Invalid Port",
+			fmt.Sprintf("Attribute destination_port can only be set for the \"tcp\", \"udp\" and \"tcp/udp\" protocols, got protocol: %q", cfg.Protocol.ValueString()),
+		)
+	}
+
+	if cfg.DestinationPort.IsNull() || cfg.DestinationPort.IsUnknown() || cfg.DestinationPortEnd.IsNull() || cfg.DestinationPortEnd.IsUnknown() {
+		return
+	}
+
+	if cfg.DestinationPortEnd.ValueInt64() < cfg.DestinationPort.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("destination_port_end"),
+			"Invalid Port Range",
+			fmt.Sprintf("Attribute destination_port_end must be greater than or equal to destination_port, got: %d", cfg.DestinationPortEnd.ValueInt64()),
+		)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *ipv6PinholeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ipv6PinholeModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg := plan.config()
+	err := r.client.UpsertPinhole(ctx, cfg)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating IPv6 pinhole",
+			fmt.Sprintf("Could not create IPv6 pinhole %q, unexpected error: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	ph, err := r.client.GetPinhole(ctx, cfg.Origin, cfg.Name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating IPv6 pinhole",
+			fmt.Sprintf("Could not read IPv6 pinhole %q after creating it: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	plan.Status = types.StringValue(ph.Status)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ipv6PinholeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ipv6PinholeModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	origin, name := state.Origin.ValueString(), state.Name.ValueString()
+	ph, err := r.client.GetPinhole(ctx, origin, name)
+	if errors.Is(err, livebox.ErrNotFound) {
+		// The pinhole has been deleted outside of Terraform, remove it from the state so it gets recreated.
+		tflog.Warn(ctx, "IPv6 pinhole not found, removing it from state", map[string]any{"origin": origin, "name": name})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading IPv6 pinhole",
+			fmt.Sprintf("Could not read state for IPv6 pinhole %q: %v", name, err),
+		)
+		return
+	}
+
+	state.setPinhole(ph)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *ipv6PinholeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ipv6PinholeModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cfg := plan.config()
+	err := r.client.UpsertPinhole(ctx, cfg)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating IPv6 pinhole",
+			fmt.Sprintf("Could not update IPv6 pinhole %q: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	ph, err := r.client.GetPinhole(ctx, cfg.Origin, cfg.Name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating IPv6 pinhole",
+			fmt.Sprintf("Could not read IPv6 pinhole %q after updating it: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	plan.Status = types.StringValue(ph.Status)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *ipv6PinholeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ipv6PinholeModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	origin, name := state.Origin.ValueString(), state.Name.ValueString()
+	err := r.client.DeletePinhole(ctx, origin, name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting IPv6 pinhole",
+			fmt.Sprintf("Could not delete IPv6 pinhole %q: %v", name, err),
+		)
+		return
+	}
+}
+
+// ImportState imports an existing IPv6 pinhole into Terraform.
+// The import ID can either be the raw Livebox ID of the pinhole (e.g. "webui_https"),
+// or the name of a pinhole created from the web interface.
+func (r *ipv6PinholeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	phs, err := r.client.ListPinholes(ctx, "")
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing IPv6 pinhole",
+			fmt.Sprintf("Could not import IPv6 pinhole %q: %v", req.ID, err),
+		)
+		return
+	}
+
+	idx := slices.IndexFunc(phs, func(ph livebox.Pinhole) bool {
+		return ph.ID == req.ID
+	})
+	if idx == -1 {
+		idx = slices.IndexFunc(phs, func(ph livebox.Pinhole) bool {
+			return ph.Origin == livebox.OriginWebUI && ph.Name == req.ID
+		})
+	}
+	if idx == -1 {
+		resp.Diagnostics.AddError(
+			"Error importing IPv6 pinhole",
+			fmt.Sprintf("Could not import IPv6 pinhole %q: no pinhole with this ID or name", req.ID),
+		)
+		return
+	}
+
+	var state ipv6PinholeModel
+	state.setPinhole(&phs[idx])
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,303 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &lanResource{}
+	_ resource.ResourceWithConfigure      = &lanResource{}
+	_ resource.ResourceWithImportState    = &lanResource{}
+	_ resource.ResourceWithValidateConfig = &lanResource{}
+)
+
+// lanResource is the resource implementation.
+type lanResource struct {
+	client *livebox.Client
+}
+
+// NewLANResource is a helper function to simplify the provider implementation.
+func NewLANResource() resource.Resource {
+	return &lanResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *lanResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the resource type name.
+func (r *lanResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_lan"
+}
+
+// Schema defines the schema for the resource.
+func (r *lanResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Configure the IPv4 addressing of the LAN of a Livebox and its DHCP server. A Livebox has a single LAN, " +
+			"so this resource should be declared at most once. Destroying it leaves the settings of the Livebox unchanged. " +
+			"Changing the IP address of the Livebox changes the address the provider must use to reach it from the LAN: if the provider `host` is the previous address, the new one is used for the rest of the run, but the provider configuration must be updated too.",
+		Attributes: map[string]schema.Attribute{
+			"ip_address": schema.StringAttribute{
+				Required:    true,
+				Description: "IPv4 address of the Livebox on the LAN.",
+				Validators: []validator.String{
+					ipv4AddressValidator{},
+				},
+			},
+			"netmask": schema.StringAttribute{
+				Required:    true,
+				Description: `Netmask of the LAN (e.g. "255.255.255.0").`,
+				Validators: []validator.String{
+					ipv4AddressValidator{},
+				},
+			},
+			"dhcp_enabled": schema.BoolAttribute{
+				Required:    true,
+				Description: "Whether the DHCP server of the Livebox is enabled or not.",
+			},
+			"dhcp_min_address": schema.StringAttribute{
+				Required:    true,
+				Description: "First IPv4 address of the DHCP pool. It must be inside the LAN.",
+				Validators: []validator.String{
+					ipv4AddressValidator{},
+				},
+			},
+			"dhcp_max_address": schema.StringAttribute{
+				Required:    true,
+				Description: "Last IPv4 address of the DHCP pool (inclusive). It must be inside the LAN.",
+				Validators: []validator.String{
+					ipv4AddressValidator{},
+				},
+			},
+			"dhcp_lease_time": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(86400),
+				Description: "Duration of the DHCP leases in seconds. Defaults to 86400 (one day).",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+type lanModel struct {
+	IPAddress      basetypes.StringValue `tfsdk:"ip_address"`
+	Netmask        basetypes.StringValue `tfsdk:"netmask"`
+	DHCPEnabled    basetypes.BoolValue   `tfsdk:"dhcp_enabled"`
+	DHCPMinAddress basetypes.StringValue `tfsdk:"dhcp_min_address"`
+	DHCPMaxAddress basetypes.StringValue `tfsdk:"dhcp_max_address"`
+	DHCPLeaseTime  basetypes.Int64Value  `tfsdk:"dhcp_lease_time"`
+}
+
+// settings returns the LAN settings described by the model.
+func (m *lanModel) settings() livebox.LANSettings {
+	return livebox.LANSettings{
+		IPAddress:      m.IPAddress.ValueString(),
+		Netmask:        m.Netmask.ValueString(),
+		DHCPEnabled:    m.DHCPEnabled.ValueBool(),
+		DHCPMinAddress: m.DHCPMinAddress.ValueString(),
+		DHCPMaxAddress: m.DHCPMaxAddress.ValueString(),
+		DHCPLeaseTime:  int(m.DHCPLeaseTime.ValueInt64()),
+	}
+}
+
+// setSettings updates every attribute of the model with the given LAN settings.
+func (m *lanModel) setSettings(s *livebox.LANSettings) {
+	m.IPAddress = types.StringValue(s.IPAddress)
+	m.Netmask = types.StringValue(s.Netmask)
+	m.DHCPEnabled = types.BoolValue(s.DHCPEnabled)
+	m.DHCPMinAddress = types.StringValue(s.DHCPMinAddress)
+	m.DHCPMaxAddress = types.StringValue(s.DHCPMaxAddress)
+	m.DHCPLeaseTime = types.Int64Value(int64(s.DHCPLeaseTime))
+}
+
+// ValidateConfig validates that the DHCP pool fits in the LAN, which depends on several attributes.
+func (r *lanResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cfg lanModel
+	diags := req.Config.Get(ctx, &cfg)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if cfg.IPAddress.IsUnknown() || cfg.Netmask.IsUnknown() || cfg.DHCPMinAddress.IsUnknown() || cfg.DHCPMaxAddress.IsUnknown() {
+		return
+	}
+
+	settings := cfg.settings()
+	prefix, err := settings.Prefix()
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("netmask"),
+			"Invalid LAN Addressing",
+			fmt.Sprintf("Could not compute the LAN prefix: %v", err),
+		)
+		return
+	}
+
+	ipAddr, ipErr := netip.ParseAddr(settings.IPAddress)
+	minAddr, minErr := netip.ParseAddr(settings.DHCPMinAddress)
+	maxAddr, maxErr := netip.ParseAddr(settings.DHCPMaxAddress)
+	if ipErr != nil || minErr != nil || maxErr != nil {
+		// Already reported by the attribute validators.
+		return
+	}
+
+	for name, addr := range map[string]netip.Addr{"dhcp_min_address": minAddr, "dhcp_max_address": maxAddr} {
+		if !prefix.Contains(addr) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid DHCP Pool",
+				fmt.Sprintf("Attribute %s must be inside the LAN %s, got: %q", name, prefix.Masked(), addr),
+			)
+		}
+	}
+
+	if minAddr.Compare(maxAddr) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dhcp_max_address"),
+			"Invalid DHCP Pool",
+			fmt.Sprintf("Attribute dhcp_max_address must not be lower than dhcp_min_address, got: %q", maxAddr),
+		)
+	}
+
+	if ipAddr.Compare(minAddr) >= 0 && ipAddr.Compare(maxAddr) <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ip_address"),
+			"Invalid DHCP Pool",
+			fmt.Sprintf("Attribute ip_address must not be inside the DHCP pool, got: %q", ipAddr),
+		)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *lanResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan lanModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetLANSettings(ctx, plan.settings())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error configuring LAN",
+			fmt.Sprintf("Could not configure LAN, unexpected error: %v", err),
+		)
+		return
+	}
+
+	// Read the settings back, from the new address of the Livebox if it changed.
+	settings, err := r.client.GetLANSettings(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading LAN",
+			fmt.Sprintf("Could not read LAN settings after they were applied: %v", err),
+		)
+		return
+	}
+
+	plan.setSettings(settings)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *lanResource) Read(ctx context.Context, _ resource.ReadRequest, resp *resource.ReadResponse) {
+	settings, err := r.client.GetLANSettings(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading LAN",
+			fmt.Sprintf("Could not read LAN settings: %v", err),
+		)
+		return
+	}
+
+	var state lanModel
+	state.setSettings(settings)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *lanResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan lanModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.SetLANSettings(ctx, plan.settings())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating LAN",
+			fmt.Sprintf("Could not update LAN settings: %v", err),
+		)
+		return
+	}
+
+	// Read the settings back, from the new address of the Livebox if it changed.
+	settings, err := r.client.GetLANSettings(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading LAN",
+			fmt.Sprintf("Could not read LAN settings after they were applied: %v", err),
+		)
+		return
+	}
+
+	plan.setSettings(settings)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the resource from the Terraform state. The LAN of a Livebox
+// cannot be removed, so its settings are left as they are.
+func (r *lanResource) Delete(ctx context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	tflog.Info(ctx, "Removing LAN from state, its settings are left unchanged on the Livebox")
+}
+
+// ImportState imports the current LAN settings of the Livebox into Terraform.
+// Since a Livebox has a single LAN, the import ID is ignored.
+func (r *lanResource) ImportState(ctx context.Context, _ resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	settings, err := r.client.GetLANSettings(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing LAN",
+			fmt.Sprintf("Could not import LAN settings: %v", err),
+		)
+		return
+	}
+
+	var state lanModel
+	state.setSettings(settings)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
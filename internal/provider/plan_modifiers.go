@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ planmodifier.Int64 = int64DefaultFromAttribute{}
+
+// int64DefaultFromAttribute sets the planned value of an unconfigured integer attribute
+// to the planned value of another attribute of the resource.
+type int64DefaultFromAttribute struct {
+	path path.Path
+}
+
+// Description describes the plan modification in plain text formatting.
+func (m int64DefaultFromAttribute) Description(_ context.Context) string {
+	return fmt.Sprintf("defaults to the value of %s if not configured", m.path)
+}
+
+// MarkdownDescription describes the plan modification in Markdown formatting.
+func (m int64DefaultFromAttribute) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+// PlanModifyInt64 performs the plan modification.
+func (m int64DefaultFromAttribute) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+
+	var value types.Int64
+	diags := req.Plan.GetAttribute(ctx, m.path, &value)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.PlanValue = value
+}
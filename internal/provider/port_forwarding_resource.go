@@ -2,19 +2,42 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/skwair/terraform-provider-livebox/livebox"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &portForwardingResource{}
-	_ resource.ResourceWithConfigure = &portForwardingResource{}
+	_ resource.Resource                   = &portForwardingResource{}
+	_ resource.ResourceWithConfigure      = &portForwardingResource{}
+	_ resource.ResourceWithImportState    = &portForwardingResource{}
+	_ resource.ResourceWithUpgradeState   = &portForwardingResource{}
+	_ resource.ResourceWithValidateConfig = &portForwardingResource{}
+	_ resource.ResourceWithModifyPlan     = &portForwardingResource{}
 )
 
 // portForwardingResource is the resource implementation.
@@ -44,55 +67,339 @@ func (r *portForwardingResource) Metadata(_ context.Context, req resource.Metada
 // Schema defines the schema for the resource.
 func (r *portForwardingResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     2,
 		Description: "Configure a port forwarding rule on a Livebox.",
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				Required:    true,
-				Description: "Arbitrary but unique name of the port forwarding rule to create.",
+				Description: "Arbitrary but unique name of the port forwarding rule to create. Changing it replaces the rule.",
+				PlanModifiers: []planmodifier.String{
+					// The name is used to build the ID of the rule on the Livebox, so renaming
+					// a rule must delete the old one instead of creating a second one next to it.
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"origin": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(livebox.OriginWebUI),
+				Description: `Origin of the port forwarding rule (e.g. "webui" or "upnp"). Defaults to "webui". Changing it replaces the rule.`,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"protocol": schema.StringAttribute{
 				Required:    true,
 				Description: `Protocol of the port forwarding rule to create. Must be one of: "tcp", "udp" or "tcp/udp"`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(livebox.ProtocolTCP), string(livebox.ProtocolUDP), string(livebox.ProtocolTCPUDP)),
+				},
 			},
 			"external_port": schema.Int64Attribute{
 				Required:    true,
 				Description: "External port of the port forwarding rule to create.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
 			},
 			"internal_port": schema.Int64Attribute{
 				Required:    true,
 				Description: "Internal port of the port forwarding rule to create.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
 			},
-			"port_range": schema.Int64Attribute{
+			"external_port_end": schema.Int64Attribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "If set, forwards a range of consecutive ports instead of one.",
+				Description: "If set, forwards the range of consecutive external ports from external_port to this port (inclusive). Defaults to external_port.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+					int64validator.AtLeastSumOf(path.MatchRoot("external_port")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultFromAttribute{path: path.Root("external_port")},
+				},
+			},
+			"internal_port_end": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Description: "If set, forwards traffic to the range of consecutive internal ports from internal_port to this port (inclusive), " +
+					"which must have the same length as the external range. Defaults to internal_port, in which case traffic to every " +
+					"external port is forwarded to internal_port. Internal ranges are not supported by every firmware.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+					int64validator.AtLeastSumOf(path.MatchRoot("internal_port")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultFromAttribute{path: path.Root("internal_port")},
+				},
 			},
 			"destination": schema.StringAttribute{
-				Required:    true,
-				Description: "IP address to forward traffic to.",
+				Required: true,
+				Description: "IP address, MAC address, name or hostname of the device to forward traffic to. MAC addresses and names are " +
+					"resolved to the current IP address of the device, as known by the Livebox. A name shared by several devices is rejected.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"source": schema.StringAttribute{
+			"destination_ip": schema.StringAttribute{
+				Computed:    true,
+				Description: "IP address traffic is forwarded to, resolved from the destination.",
+			},
+			"sources": schema.SetAttribute{
+				ElementType: types.StringType,
 				Optional:    true,
 				Computed:    true,
-				Description: "Allowed external IP addresses to forward traffic from, separated by commas.",
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+				Description: "Allowed external IP addresses or IPv4 CIDR prefixes (e.g. \"192.0.2.0/24\") to forward traffic from. If empty, traffic from any address is forwarded.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(sourcePrefixValidator{}),
+				},
 			},
 			"enabled": schema.BoolAttribute{
 				Required:    true,
 				Description: "Whether this port forwarding rule is enabled or not.",
 			},
+			"hairpin_nat": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Whether hosts of the LAN can reach the forwarded port through the public IP address of the Livebox. " +
+					"If not set, new rules enable it and existing rules keep their current value.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"symmetric_snat": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Whether the source address of hairpinned traffic is rewritten so replies go back through the Livebox. " +
+					"If not set, new rules disable it and existing rules keep their current value.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"upnpv1_compat": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the rule is compatible with UPnP IGD v1 clients.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"lease_duration": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Duration of the rule in seconds, 0 meaning it never expires.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: `Status of the rule as reported by the Livebox (e.g. "Enabled", "Disabled" or "Error").`,
+			},
+			"destination_mac_address": schema.StringAttribute{
+				Computed:    true,
+				Description: "MAC address of the device traffic is forwarded to, as resolved by the Livebox.",
+			},
+			"source_interface": schema.StringAttribute{
+				Computed:    true,
+				Description: "Interface of the Livebox the rule applies to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
 
+// ValidateConfig validates the port ranges of the configuration, which depend on several attributes.
+func (r *portForwardingResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cfg portForwardingModel
+	diags := req.Config.Get(ctx, &cfg)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if cfg.InternalPortEnd.IsNull() || cfg.InternalPortEnd.IsUnknown() || cfg.InternalPort.IsUnknown() ||
+		cfg.ExternalPort.IsUnknown() || cfg.ExternalPortEnd.IsUnknown() {
+		return
+	}
+
+	externalEnd := cfg.ExternalPortEnd.ValueInt64()
+	if cfg.ExternalPortEnd.IsNull() {
+		externalEnd = cfg.ExternalPort.ValueInt64()
+	}
+
+	internalLen := cfg.InternalPortEnd.ValueInt64() - cfg.InternalPort.ValueInt64()
+	if externalLen := externalEnd - cfg.ExternalPort.ValueInt64(); internalLen != 0 && internalLen != externalLen {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("internal_port_end"),
+			"Invalid Internal Port Range",
+			fmt.Sprintf("The internal port range must have the same length as the external port range (%d ports), got %d ports.", externalLen+1, internalLen+1),
+		)
+	}
+}
+
 type portForwardingModel struct {
-	Name         basetypes.StringValue `tfsdk:"name"`
-	Protocol     basetypes.StringValue `tfsdk:"protocol"`
-	ExternalPort basetypes.Int64Value  `tfsdk:"external_port"`
-	InternalPort basetypes.Int64Value  `tfsdk:"internal_port"`
-	PortRange    basetypes.Int64Value  `tfsdk:"port_range"`
-	Destination  basetypes.StringValue `tfsdk:"destination"`
-	Source       basetypes.StringValue `tfsdk:"source"`
-	Enabled      basetypes.BoolValue   `tfsdk:"enabled"`
+	Name                  basetypes.StringValue `tfsdk:"name"`
+	Origin                basetypes.StringValue `tfsdk:"origin"`
+	Protocol              basetypes.StringValue `tfsdk:"protocol"`
+	ExternalPort          basetypes.Int64Value  `tfsdk:"external_port"`
+	InternalPort          basetypes.Int64Value  `tfsdk:"internal_port"`
+	ExternalPortEnd       basetypes.Int64Value  `tfsdk:"external_port_end"`
+	InternalPortEnd       basetypes.Int64Value  `tfsdk:"internal_port_end"`
+	Destination           basetypes.StringValue `tfsdk:"destination"`
+	DestinationIP         basetypes.StringValue `tfsdk:"destination_ip"`
+	Sources               basetypes.SetValue    `tfsdk:"sources"`
+	Enabled               basetypes.BoolValue   `tfsdk:"enabled"`
+	HairpinNAT            basetypes.BoolValue   `tfsdk:"hairpin_nat"`
+	SymmetricSNAT         basetypes.BoolValue   `tfsdk:"symmetric_snat"`
+	UPnPV1Compat          basetypes.BoolValue   `tfsdk:"upnpv1_compat"`
+	LeaseDuration         basetypes.Int64Value  `tfsdk:"lease_duration"`
+	Status                basetypes.StringValue `tfsdk:"status"`
+	DestinationMACAddress basetypes.StringValue `tfsdk:"destination_mac_address"`
+	SourceInterface       basetypes.StringValue `tfsdk:"source_interface"`
+}
+
+// config returns the configuration of the port forwarding rule described by the model.
+func (m *portForwardingModel) config() livebox.PortForwardingConfig {
+	return livebox.PortForwardingConfig{
+		Name:            m.Name.ValueString(),
+		Origin:          m.Origin.ValueString(),
+		ExternalPort:    int(m.ExternalPort.ValueInt64()),
+		ExternalPortEnd: int(m.ExternalPortEnd.ValueInt64()),
+		InternalPort:    int(m.InternalPort.ValueInt64()),
+		InternalPortEnd: int(m.InternalPortEnd.ValueInt64()),
+		Protocol:        livebox.Protocol(m.Protocol.ValueString()),
+		Destination:     m.DestinationIP.ValueString(),
+		Source:          normalizeAddressList(joinSources(m.Sources)),
+		Enabled:         m.Enabled.ValueBool(),
+		HairpinNAT:      m.HairpinNAT.ValueBool(),
+		SymmetricSNAT:   m.SymmetricSNAT.ValueBool(),
+	}
+}
+
+// setNATDefaults sets the NAT attributes that are not configured and have no prior value to keep, which
+// makes them unknown in the plan. They take the value of the given rule currently configured on the Livebox,
+// if any, so that applying a configuration that does not mention them never flips them. New rules get the
+// same values as the ones created from the web interface.
+func (m *portForwardingModel) setNATDefaults(current *livebox.PortForwarding) {
+	hairpinNAT, symmetricSNAT := true, false
+	if current != nil {
+		hairpinNAT, symmetricSNAT = current.HairpinNAT, current.SymmetricSNAT
+	}
+
+	if m.HairpinNAT.IsUnknown() {
+		m.HairpinNAT = types.BoolValue(hairpinNAT)
+	}
+	if m.SymmetricSNAT.IsUnknown() {
+		m.SymmetricSNAT = types.BoolValue(symmetricSNAT)
+	}
+}
+
+// setConfig updates the model with the values of the given configuration, once it has been applied.
+func (m *portForwardingModel) setConfig(cfg livebox.PortForwardingConfig) {
+	m.Name = types.StringValue(cfg.Name)
+	m.Origin = types.StringValue(cfg.Origin)
+	m.Protocol = types.StringValue(string(cfg.Protocol))
+	m.DestinationIP = types.StringValue(cfg.Destination)
+	m.ExternalPort = types.Int64Value(int64(cfg.ExternalPort))
+	m.ExternalPortEnd = types.Int64Value(int64(cfg.ExternalPortEnd))
+	m.InternalPort = types.Int64Value(int64(cfg.InternalPort))
+	m.InternalPortEnd = types.Int64Value(int64(cfg.InternalPortEnd))
+	m.Enabled = types.BoolValue(cfg.Enabled)
+	m.HairpinNAT = types.BoolValue(cfg.HairpinNAT)
+	m.SymmetricSNAT = types.BoolValue(cfg.SymmetricSNAT)
+}
+
+// setComputed updates the read-only attributes of the model with the values of the given rule.
+func (m *portForwardingModel) setComputed(pf *livebox.PortForwarding) {
+	m.UPnPV1Compat = types.BoolValue(pf.UPnPV1Compat)
+	m.LeaseDuration = types.Int64Value(int64(pf.LeaseDuration))
+	m.Status = types.StringValue(pf.Status)
+	m.DestinationMACAddress = types.StringValue(pf.DestinationMACAddress)
+	m.SourceInterface = types.StringValue(pf.SourceInterface)
+}
+
+// setPortForwarding updates every attribute of the model with the values of the given rule.
+func (m *portForwardingModel) setPortForwarding(pf *livebox.PortForwarding) {
+	m.Name = types.StringValue(pf.Name)
+	m.Origin = types.StringValue(pf.Origin)
+	m.Protocol = types.StringValue(string(pf.Protocol))
+	m.ExternalPort = types.Int64Value(int64(pf.ExternalPort))
+	m.ExternalPortEnd = types.Int64Value(int64(pf.ExternalPortEnd))
+	m.InternalPort = types.Int64Value(int64(pf.InternalPort))
+	m.InternalPortEnd = types.Int64Value(int64(pf.InternalPortEnd))
+	m.DestinationIP = types.StringValue(pf.Destination)
+	m.Enabled = types.BoolValue(pf.Enabled)
+
+	// The destination is only refreshed if it is an IP address. Devices referenced by their MAC address or name
+	// are resolved again when planning, so a change of their IP address shows up as a diff on destination_ip.
+	if m.Destination.IsNull() || net.ParseIP(m.Destination.ValueString()) != nil {
+		m.Destination = types.StringValue(pf.Destination)
+	}
+	m.HairpinNAT = types.BoolValue(pf.HairpinNAT)
+	m.SymmetricSNAT = types.BoolValue(pf.SymmetricSNAT)
+
+	// Only overwrite the sources if they actually changed, so that addresses written in a different
+	// notation in the configuration do not produce a diff.
+	if m.Sources.IsNull() || normalizeAddressList(joinSources(m.Sources)) != normalizeAddressList(pf.Source) {
+		m.Sources = splitSources(pf.Source)
+	}
+
+	m.setComputed(pf)
+}
+
+// ModifyPlan resolves the destination of the port forwarding rule, so that the planned destination_ip
+// reflects the current IP address of the targeted device.
+func (r *portForwardingResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to resolve when the resource is being destroyed or the provider is not configured yet.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var destination types.String
+	diags := req.Plan.GetAttribute(ctx, path.Root("destination"), &destination)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || destination.IsUnknown() {
+		return
+	}
+
+	ip, err := r.resolveDestination(ctx, destination.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("destination"),
+			"Error resolving port forward destination",
+			fmt.Sprintf("Could not resolve destination %q: %v", destination.ValueString(), err),
+		)
+		return
+	}
+
+	diags = resp.Plan.SetAttribute(ctx, path.Root("destination_ip"), ip)
+	resp.Diagnostics.Append(diags...)
+}
+
+// resolveDestination returns the IP address of the given destination, which is either an IP address,
+// or the MAC address or name of a device known by the Livebox.
+func (r *portForwardingResource) resolveDestination(ctx context.Context, destination string) (string, error) {
+	if net.ParseIP(destination) != nil {
+		return destination, nil
+	}
+
+	device, err := r.client.GetDevice(ctx, destination)
+	if err != nil {
+		return "", err
+	}
+
+	if device.IPAddress == "" {
+		return "", fmt.Errorf("device %q has no IP address", destination)
+	}
+
+	return device.IPAddress, nil
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -104,34 +411,43 @@ func (r *portForwardingResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	cfg := livebox.PortForwardingConfig{
-		Name:         plan.Name.ValueString(),
-		ExternalPort: int(plan.ExternalPort.ValueInt64()),
-		InternalPort: int(plan.InternalPort.ValueInt64()),
-		PortRange:    int(plan.PortRange.ValueInt64()),
-		Protocol:     livebox.Protocol(plan.Protocol.ValueString()),
-		Destination:  plan.Destination.ValueString(),
-		Source:       plan.Source.ValueString(),
-		Enabled:      plan.Enabled.ValueBool(),
+	if plan.DestinationIP.IsUnknown() {
+		// The destination was not known when planning, resolve it now.
+		ip, err := r.resolveDestination(ctx, plan.Destination.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("destination"),
+				"Error resolving port forward destination",
+				fmt.Sprintf("Could not resolve destination %q: %v", plan.Destination.ValueString(), err),
+			)
+			return
+		}
+		plan.DestinationIP = types.StringValue(ip)
 	}
 
-	err := r.client.UpsertPortForwarding(cfg)
+	plan.setNATDefaults(nil)
+
+	cfg := plan.config()
+	err := r.client.UpsertPortForwarding(ctx, cfg)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addUpsertPortForwardingError(&resp.Diagnostics, err, portForwardingConflictPath,
 			"Error creating port forward",
 			fmt.Sprintf("Could not create port forward %q, unexpected error: %v", cfg.Name, err),
 		)
 		return
 	}
 
-	plan.Name = types.StringValue(cfg.Name)
-	plan.Protocol = types.StringValue(string(cfg.Protocol))
-	plan.Destination = types.StringValue(cfg.Destination)
-	plan.Source = types.StringValue(cfg.Source)
-	plan.ExternalPort = types.Int64Value(int64(cfg.ExternalPort))
-	plan.InternalPort = types.Int64Value(int64(cfg.InternalPort))
-	plan.PortRange = types.Int64Value(int64(cfg.PortRange))
-	plan.Enabled = types.BoolValue(cfg.Enabled)
+	pf, err := r.client.GetPortForwarding(ctx, cfg.Origin, cfg.Name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating port forward",
+			fmt.Sprintf("Could not read port forward %q after creating it: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	plan.setConfig(cfg)
+	plan.setComputed(pf)
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -149,8 +465,16 @@ func (r *portForwardingResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	name := state.Name.ValueString()
-	pf, err := r.client.GetPortForwarding(name)
+	// The origin may be null in states written before it was introduced, in which case
+	// the client falls back to the web interface origin, which was the only one supported.
+	origin, name := state.Origin.ValueString(), state.Name.ValueString()
+	pf, err := r.client.GetPortForwarding(ctx, origin, name)
+	if errors.Is(err, livebox.ErrNotFound) {
+		// The rule has been deleted outside of Terraform, remove it from the state so it gets recreated.
+		tflog.Warn(ctx, "Port forward not found, removing it from state", map[string]any{"origin": origin, "name": name})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting port forward start",
@@ -159,13 +483,7 @@ func (r *portForwardingResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	state.Name = types.StringValue(pf.Name)
-	state.Protocol = types.StringValue(string(pf.Protocol))
-	state.ExternalPort = types.Int64Value(int64(pf.ExternalPort))
-	state.InternalPort = types.Int64Value(int64(pf.InternalPort))
-	state.PortRange = types.Int64Value(int64(pf.PortRange))
-	state.Destination = types.StringValue(pf.Destination)
-	state.Enabled = types.BoolValue(pf.Enabled)
+	state.setPortForwarding(pf)
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -183,34 +501,55 @@ func (r *portForwardingResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	cfg := livebox.PortForwardingConfig{
-		Name:         plan.Name.ValueString(),
-		ExternalPort: int(plan.ExternalPort.ValueInt64()),
-		InternalPort: int(plan.InternalPort.ValueInt64()),
-		PortRange:    int(plan.PortRange.ValueInt64()),
-		Protocol:     livebox.Protocol(plan.Protocol.ValueString()),
-		Destination:  plan.Destination.ValueString(),
-		Source:       plan.Source.ValueString(),
-		Enabled:      plan.Enabled.ValueBool(),
+	if plan.DestinationIP.IsUnknown() {
+		// The destination was not known when planning, resolve it now.
+		ip, err := r.resolveDestination(ctx, plan.Destination.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("destination"),
+				"Error resolving port forward destination",
+				fmt.Sprintf("Could not resolve destination %q: %v", plan.Destination.ValueString(), err),
+			)
+			return
+		}
+		plan.DestinationIP = types.StringValue(ip)
 	}
 
-	err := r.client.UpsertPortForwarding(cfg)
+	if plan.HairpinNAT.IsUnknown() || plan.SymmetricSNAT.IsUnknown() {
+		// There was no prior value to keep, e.g. in states written before these attributes existed
+		// and not refreshed since, keep the values the rule currently has.
+		current, err := r.client.GetPortForwarding(ctx, plan.Origin.ValueString(), plan.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating port forward",
+				fmt.Sprintf("Could not read port forward %q before updating it: %v", plan.Name.ValueString(), err),
+			)
+			return
+		}
+		plan.setNATDefaults(current)
+	}
+
+	cfg := plan.config()
+	err := r.client.UpsertPortForwarding(ctx, cfg)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		addUpsertPortForwardingError(&resp.Diagnostics, err, portForwardingConflictPath,
 			"Error updating port forward",
 			fmt.Sprintf("Could not update port forward %q: %v", cfg.Name, err),
 		)
 		return
 	}
 
-	plan.Name = types.StringValue(cfg.Name)
-	plan.Protocol = types.StringValue(string(cfg.Protocol))
-	plan.Destination = types.StringValue(cfg.Destination)
-	plan.Source = types.StringValue(cfg.Source)
-	plan.ExternalPort = types.Int64Value(int64(cfg.ExternalPort))
-	plan.InternalPort = types.Int64Value(int64(cfg.InternalPort))
-	plan.PortRange = types.Int64Value(int64(cfg.PortRange))
-	plan.Enabled = types.BoolValue(cfg.Enabled)
+	pf, err := r.client.GetPortForwarding(ctx, cfg.Origin, cfg.Name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating port forward",
+			fmt.Sprintf("Could not read port forward %q after updating it: %v", cfg.Name, err),
+		)
+		return
+	}
+
+	plan.setConfig(cfg)
+	plan.setComputed(pf)
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -228,8 +567,8 @@ func (r *portForwardingResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
-	name := state.Name.ValueString()
-	err := r.client.DeletePortForwarding(name)
+	origin, name := state.Origin.ValueString(), state.Name.ValueString()
+	err := r.client.DeletePortForwarding(ctx, origin, name)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting port forward",
@@ -238,3 +577,151 @@ func (r *portForwardingResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 }
+
+// ImportState imports an existing port forwarding rule into Terraform.
+// The import ID can either be the raw Livebox ID of the rule (e.g. "webui_wireguard" or "upnp_..."),
+// or the name of a rule created from the web interface.
+func (r *portForwardingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	pfs, err := r.client.ListPortForwardings(ctx, "")
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing port forward",
+			fmt.Sprintf("Could not import port forward %q: %v", req.ID, err),
+		)
+		return
+	}
+
+	idx := slices.IndexFunc(pfs, func(pf livebox.PortForwarding) bool {
+		return pf.ID == req.ID
+	})
+	if idx == -1 {
+		idx = slices.IndexFunc(pfs, func(pf livebox.PortForwarding) bool {
+			return pf.Origin == livebox.OriginWebUI && pf.Name == req.ID
+		})
+	}
+	if idx == -1 {
+		resp.Diagnostics.AddError(
+			"Error importing port forward",
+			fmt.Sprintf("Could not import port forward %q: no rule with this ID or name", req.ID),
+		)
+		return
+	}
+
+	var state portForwardingModel
+	state.setPortForwarding(&pfs[idx])
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// UpgradeState upgrades the state of port forwarding rules written by previous versions of the schema.
+func (r *portForwardingResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored the allowed sources as a single string of addresses separated by commas.
+		0: {
+			PriorSchema: &portForwardingSchemaV0,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior portForwardingModelV0
+				diags := req.State.Get(ctx, &prior)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				diags = resp.State.Set(ctx, prior.upgrade().upgrade())
+				resp.Diagnostics.Append(diags...)
+			},
+		},
+		// Version 1 stored port ranges as a number of ports following the external port.
+		1: {
+			PriorSchema: &portForwardingSchemaV1,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior portForwardingModelV1
+				diags := req.State.Get(ctx, &prior)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				diags = resp.State.Set(ctx, prior.upgrade())
+				resp.Diagnostics.Append(diags...)
+			},
+		},
+	}
+}
+
+// joinSources returns the elements of the given set of sources separated by commas.
+func joinSources(sources basetypes.SetValue) string {
+	elems := make([]string, 0, len(sources.Elements()))
+	for _, elem := range sources.Elements() {
+		if s, ok := elem.(basetypes.StringValue); ok {
+			elems = append(elems, s.ValueString())
+		}
+	}
+
+	return strings.Join(elems, ",")
+}
+
+// addUpsertPortForwardingError adds the diagnostic matching an error returned while writing port forwards.
+// Conflicts with existing rules are reported on the attribute returned by conflictPath for the name of the
+// rule that could not be written, other errors are reported with the given summary and detail.
+func addUpsertPortForwardingError(diags *diag.Diagnostics, err error, conflictPath func(name string) path.Path, summary, detail string) {
+	var conflict *livebox.PortForwardingConflictError
+	if errors.As(err, &conflict) {
+		diags.AddAttributeError(conflictPath(conflict.Name), "Conflicting port forward", conflict.Error())
+		return
+	}
+
+	diags.AddError(summary, detail)
+}
+
+// portForwardingConflictPath returns the path of the attribute on which port forwarding conflicts are reported.
+func portForwardingConflictPath(string) path.Path {
+	return path.Root("external_port")
+}
+
+// splitSources does the opposite of joinSources. It returns the given list of sources separated by commas as a set.
+func splitSources(list string) basetypes.SetValue {
+	elems := []attr.Value{}
+	for _, src := range strings.Split(list, ",") {
+		if src = strings.TrimSpace(src); src != "" && !slices.Contains(elems, attr.Value(types.StringValue(src))) {
+			elems = append(elems, types.StringValue(src))
+		}
+	}
+
+	return types.SetValueMust(types.StringType, elems)
+}
+
+// normalizeAddressList returns the given list of addresses separated by commas
+// sorted, in their canonical form and without whitespace or empty elements.
+func normalizeAddressList(list string) string {
+	var addrs []string
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, canonicalAddress(addr))
+		}
+	}
+
+	slices.Sort(addrs)
+
+	return strings.Join(addrs, ",")
+}
+
+// canonicalAddress returns the canonical form of the given IP address or CIDR prefix, so that different
+// notations of the same source compare equal. Prefixes are masked and single IP prefixes (e.g. "/32") are
+// written as plain addresses. Invalid addresses are returned as is.
+func canonicalAddress(addr string) string {
+	if prefix, err := netip.ParsePrefix(addr); err == nil {
+		if prefix = prefix.Masked(); prefix.IsSingleIP() {
+			return prefix.Addr().String()
+		}
+
+		return prefix.String()
+	}
+
+	if ip, err := netip.ParseAddr(addr); err == nil {
+		return ip.String()
+	}
+
+	return addr
+}
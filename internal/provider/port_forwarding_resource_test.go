@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+func TestCanonicalAddress(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "192.0.2.1", want: "192.0.2.1"},
+		{in: "192.0.2.1/32", want: "192.0.2.1"},
+		{in: "192.0.2.7/24", want: "192.0.2.0/24"},
+		{in: "192.0.2.0/24", want: "192.0.2.0/24"},
+		{in: "2001:DB8::0:1", want: "2001:db8::1"},
+		{in: "2001:0db8:0000::1/128", want: "2001:db8::1"},
+		{in: "2001:db8::1/64", want: "2001:db8::/64"},
+		{in: "not an address", want: "not an address"},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := canonicalAddress(tt.in); got != tt.want {
+			t.Errorf("canonicalAddress(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAddressList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: " , ,", want: ""},
+		{in: "192.0.2.1", want: "192.0.2.1"},
+		{in: "198.51.100.0/24,192.0.2.1", want: "192.0.2.1,198.51.100.0/24"},
+		{in: " 192.0.2.1 ,  198.51.100.7/24 ,", want: "192.0.2.1,198.51.100.0/24"},
+		{in: "192.0.2.1/32,2001:DB8::1", want: "192.0.2.1,2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeAddressList(tt.in); got != tt.want {
+			t.Errorf("normalizeAddressList(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPortForwardingModelSetPortForwardingSources(t *testing.T) {
+	sources := func(elems ...string) types.Set {
+		values := make([]attr.Value, 0, len(elems))
+		for _, elem := range elems {
+			values = append(values, types.StringValue(elem))
+		}
+
+		return types.SetValueMust(types.StringType, values)
+	}
+
+	tests := map[string]struct {
+		prior  types.Set
+		source string
+		want   types.Set
+	}{
+		"same sources in another order": {
+			prior:  sources("198.51.100.0/24", "192.0.2.1"),
+			source: "192.0.2.1,198.51.100.0/24",
+			want:   sources("198.51.100.0/24", "192.0.2.1"),
+		},
+		"same sources in another notation": {
+			prior:  sources("192.0.2.1/32", "198.51.100.7/24", "2001:DB8::1"),
+			source: "192.0.2.1,198.51.100.0/24,2001:db8::1",
+			want:   sources("192.0.2.1/32", "198.51.100.7/24", "2001:DB8::1"),
+		},
+		"no sources": {
+			prior:  sources(),
+			source: "",
+			want:   sources(),
+		},
+		"changed sources": {
+			prior:  sources("192.0.2.1"),
+			source: "192.0.2.2",
+			want:   sources("192.0.2.2"),
+		},
+		"sources added outside of terraform": {
+			prior:  sources(),
+			source: "192.0.2.1",
+			want:   sources("192.0.2.1"),
+		},
+		"imported": {
+			prior:  types.SetNull(types.StringType),
+			source: "192.0.2.1, 198.51.100.0/24",
+			want:   sources("192.0.2.1", "198.51.100.0/24"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := portForwardingModel{Sources: tt.prior}
+			m.setPortForwarding(&livebox.PortForwarding{
+				Name:            "test",
+				Origin:          livebox.OriginWebUI,
+				Protocol:        livebox.ProtocolTCP,
+				ExternalPort:    443,
+				ExternalPortEnd: 443,
+				InternalPort:    443,
+				InternalPortEnd: 443,
+				Destination:     "192.168.1.10",
+				Source:          tt.source,
+			})
+
+			if !m.Sources.Equal(tt.want) {
+				t.Errorf("got sources %s, want %s", m.Sources, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortForwardingModelSetNATDefaults(t *testing.T) {
+	tests := map[string]struct {
+		hairpinNAT, symmetricSNAT         types.Bool
+		current                           *livebox.PortForwarding
+		wantHairpinNAT, wantSymmetricSNAT bool
+	}{
+		"new rule": {
+			hairpinNAT:        types.BoolUnknown(),
+			symmetricSNAT:     types.BoolUnknown(),
+			wantHairpinNAT:    true,
+			wantSymmetricSNAT: false,
+		},
+		"existing rule keeps its values": {
+			hairpinNAT:        types.BoolUnknown(),
+			symmetricSNAT:     types.BoolUnknown(),
+			current:           &livebox.PortForwarding{HairpinNAT: false, SymmetricSNAT: true},
+			wantHairpinNAT:    false,
+			wantSymmetricSNAT: true,
+		},
+		"configured values are kept": {
+			hairpinNAT:        types.BoolValue(true),
+			symmetricSNAT:     types.BoolValue(false),
+			current:           &livebox.PortForwarding{HairpinNAT: false, SymmetricSNAT: true},
+			wantHairpinNAT:    true,
+			wantSymmetricSNAT: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := portForwardingModel{HairpinNAT: tt.hairpinNAT, SymmetricSNAT: tt.symmetricSNAT}
+			m.setNATDefaults(tt.current)
+
+			if m.HairpinNAT.IsUnknown() || m.HairpinNAT.ValueBool() != tt.wantHairpinNAT {
+				t.Errorf("got hairpin_nat %s, want %t", m.HairpinNAT, tt.wantHairpinNAT)
+			}
+			if m.SymmetricSNAT.IsUnknown() || m.SymmetricSNAT.ValueBool() != tt.wantSymmetricSNAT {
+				t.Errorf("got symmetric_snat %s, want %t", m.SymmetricSNAT, tt.wantSymmetricSNAT)
+			}
+		})
+	}
+}
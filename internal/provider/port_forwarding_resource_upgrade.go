@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// portForwardingSchemaV0 is the version 0 of the port forwarding resource schema,
+// where the allowed sources were stored as a single string of addresses separated by commas.
+var portForwardingSchemaV0 = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"name":                    schema.StringAttribute{Required: true},
+		"origin":                  schema.StringAttribute{Optional: true, Computed: true},
+		"protocol":                schema.StringAttribute{Required: true},
+		"external_port":           schema.Int64Attribute{Required: true},
+		"internal_port":           schema.Int64Attribute{Required: true},
+		"port_range":              schema.Int64Attribute{Optional: true, Computed: true},
+		"destination":             schema.StringAttribute{Required: true},
+		"source":                  schema.StringAttribute{Optional: true, Computed: true},
+		"enabled":                 schema.BoolAttribute{Required: true},
+		"hairpin_nat":             schema.BoolAttribute{Optional: true, Computed: true},
+		"symmetric_snat":          schema.BoolAttribute{Optional: true, Computed: true},
+		"upnpv1_compat":           schema.BoolAttribute{Computed: true},
+		"lease_duration":          schema.Int64Attribute{Computed: true},
+		"status":                  schema.StringAttribute{Computed: true},
+		"destination_mac_address": schema.StringAttribute{Computed: true},
+		"source_interface":        schema.StringAttribute{Computed: true},
+	},
+}
+
+type portForwardingModelV0 struct {
+	Name                  basetypes.StringValue `tfsdk:"name"`
+	Origin                basetypes.StringValue `tfsdk:"origin"`
+	Protocol              basetypes.StringValue `tfsdk:"protocol"`
+	ExternalPort          basetypes.Int64Value  `tfsdk:"external_port"`
+	InternalPort          basetypes.Int64Value  `tfsdk:"internal_port"`
+	PortRange             basetypes.Int64Value  `tfsdk:"port_range"`
+	Destination           basetypes.StringValue `tfsdk:"destination"`
+	Source                basetypes.StringValue `tfsdk:"source"`
+	Enabled               basetypes.BoolValue   `tfsdk:"enabled"`
+	HairpinNAT            basetypes.BoolValue   `tfsdk:"hairpin_nat"`
+	SymmetricSNAT         basetypes.BoolValue   `tfsdk:"symmetric_snat"`
+	UPnPV1Compat          basetypes.BoolValue   `tfsdk:"upnpv1_compat"`
+	LeaseDuration         basetypes.Int64Value  `tfsdk:"lease_duration"`
+	Status                basetypes.StringValue `tfsdk:"status"`
+	DestinationMACAddress basetypes.StringValue `tfsdk:"destination_mac_address"`
+	SourceInterface       basetypes.StringValue `tfsdk:"source_interface"`
+}
+
+// upgrade returns the version 1 model matching the version 0 model.
+// Attributes that did not exist in the earliest releases are left null and get populated by the next refresh.
+func (m portForwardingModelV0) upgrade() portForwardingModelV1 {
+	return portForwardingModelV1{
+		Name:                  m.Name,
+		Origin:                m.Origin,
+		Protocol:              m.Protocol,
+		ExternalPort:          m.ExternalPort,
+		InternalPort:          m.InternalPort,
+		PortRange:             m.PortRange,
+		Destination:           m.Destination,
+		Sources:               splitSources(m.Source.ValueString()),
+		Enabled:               m.Enabled,
+		HairpinNAT:            m.HairpinNAT,
+		SymmetricSNAT:         m.SymmetricSNAT,
+		UPnPV1Compat:          m.UPnPV1Compat,
+		LeaseDuration:         m.LeaseDuration,
+		Status:                m.Status,
+		DestinationMACAddress: m.DestinationMACAddress,
+		SourceInterface:       m.SourceInterface,
+	}
+}
+
+// portForwardingSchemaV1 is the version 1 of the port forwarding resource schema,
+// where port ranges were stored as a number of ports following the external port.
+var portForwardingSchemaV1 = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"name":                    schema.StringAttribute{Required: true},
+		"origin":                  schema.StringAttribute{Optional: true, Computed: true},
+		"protocol":                schema.StringAttribute{Required: true},
+		"external_port":           schema.Int64Attribute{Required: true},
+		"internal_port":           schema.Int64Attribute{Required: true},
+		"port_range":              schema.Int64Attribute{Optional: true, Computed: true},
+		"destination":             schema.StringAttribute{Required: true},
+		"sources":                 schema.SetAttribute{ElementType: types.StringType, Optional: true, Computed: true},
+		"enabled":                 schema.BoolAttribute{Required: true},
+		"hairpin_nat":             schema.BoolAttribute{Optional: true, Computed: true},
+		"symmetric_snat":          schema.BoolAttribute{Optional: true, Computed: true},
+		"upnpv1_compat":           schema.BoolAttribute{Computed: true},
+		"lease_duration":          schema.Int64Attribute{Computed: true},
+		"status":                  schema.StringAttribute{Computed: true},
+		"destination_mac_address": schema.StringAttribute{Computed: true},
+		"source_interface":        schema.StringAttribute{Computed: true},
+	},
+}
+
+type portForwardingModelV1 struct {
+	Name                  basetypes.StringValue `tfsdk:"name"`
+	Origin                basetypes.StringValue `tfsdk:"origin"`
+	Protocol              basetypes.StringValue `tfsdk:"protocol"`
+	ExternalPort          basetypes.Int64Value  `tfsdk:"external_port"`
+	InternalPort          basetypes.Int64Value  `tfsdk:"internal_port"`
+	PortRange             basetypes.Int64Value  `tfsdk:"port_range"`
+	Destination           basetypes.StringValue `tfsdk:"destination"`
+	Sources               basetypes.SetValue    `tfsdk:"sources"`
+	Enabled               basetypes.BoolValue   `tfsdk:"enabled"`
+	HairpinNAT            basetypes.BoolValue   `tfsdk:"hairpin_nat"`
+	SymmetricSNAT         basetypes.BoolValue   `tfsdk:"symmetric_snat"`
+	UPnPV1Compat          basetypes.BoolValue   `tfsdk:"upnpv1_compat"`
+	LeaseDuration         basetypes.Int64Value  `tfsdk:"lease_duration"`
+	Status                basetypes.StringValue `tfsdk:"status"`
+	DestinationMACAddress basetypes.StringValue `tfsdk:"destination_mac_address"`
+	SourceInterface       basetypes.StringValue `tfsdk:"source_interface"`
+}
+
+// upgrade returns the current model matching the version 1 model.
+// A port range of 0 or 1 used to mean a single port. Any mismatch with the rule actually
+// configured on the Livebox is fixed by the next refresh.
+func (m portForwardingModelV1) upgrade() portForwardingModel {
+	externalPortEnd := m.ExternalPort
+	if portRange := m.PortRange.ValueInt64(); portRange > 1 {
+		externalPortEnd = types.Int64Value(m.ExternalPort.ValueInt64() + portRange)
+	}
+
+	return portForwardingModel{
+		Name:                  m.Name,
+		Origin:                m.Origin,
+		Protocol:              m.Protocol,
+		ExternalPort:          m.ExternalPort,
+		ExternalPortEnd:       externalPortEnd,
+		InternalPort:          m.InternalPort,
+		InternalPortEnd:       m.InternalPort,
+		Destination:           m.Destination,
+		Sources:               m.Sources,
+		Enabled:               m.Enabled,
+		HairpinNAT:            m.HairpinNAT,
+		SymmetricSNAT:         m.SymmetricSNAT,
+		UPnPV1Compat:          m.UPnPV1Compat,
+		LeaseDuration:         m.LeaseDuration,
+		Status:                m.Status,
+		DestinationMACAddress: m.DestinationMACAddress,
+		SourceInterface:       m.SourceInterface,
+	}
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// upgradePortForwardingState runs the state upgrader of the port forwarding resource for the given version
+// on a prior state made of the given attributes, the others being null, and returns the upgraded model.
+func upgradePortForwardingState(t *testing.T, version int64, attrs map[string]tftypes.Value) portForwardingModel {
+	t.Helper()
+
+	ctx := context.Background()
+	r := NewPortForwardingResource().(*portForwardingResource)
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	upgrader := r.UpgradeState(ctx)[version]
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := make(map[string]tftypes.Value, len(priorType.AttributeTypes))
+	for name, typ := range priorType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	for name, v := range attrs {
+		values[name] = v
+	}
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{
+			Schema: *upgrader.PriorSchema,
+			Raw:    tftypes.NewValue(priorType, values),
+		},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("upgrade state from version %d: %v", version, resp.Diagnostics)
+	}
+
+	var m portForwardingModel
+	if diags := resp.State.Get(ctx, &m); diags.HasError() {
+		t.Fatalf("get upgraded state: %v", diags)
+	}
+
+	return m
+}
+
+// setStrings returns the sorted elements of the given set of strings.
+func setStrings(s basetypes.SetValue) []string {
+	out := []string{}
+	for _, elem := range s.Elements() {
+		out = append(out, elem.(basetypes.StringValue).ValueString())
+	}
+	slices.Sort(out)
+
+	return out
+}
+
+func TestPortForwardingUpgradeStateV0(t *testing.T) {
+	tests := map[string]struct {
+		source tftypes.Value
+		want   []string
+	}{
+		"single address": {
+			source: tftypes.NewValue(tftypes.String, "192.0.2.1"),
+			want:   []string{"192.0.2.1"},
+		},
+		"comma-separated list": {
+			source: tftypes.NewValue(tftypes.String, "198.51.100.0/24,192.0.2.1"),
+			want:   []string{"192.0.2.1", "198.51.100.0/24"},
+		},
+		"whitespace": {
+			source: tftypes.NewValue(tftypes.String, " 192.0.2.1 , 198.51.100.0/24 ,"),
+			want:   []string{"192.0.2.1", "198.51.100.0/24"},
+		},
+		"duplicates": {
+			source: tftypes.NewValue(tftypes.String, "192.0.2.1,192.0.2.1"),
+			want:   []string{"192.0.2.1"},
+		},
+		"empty string": {
+			source: tftypes.NewValue(tftypes.String, ""),
+			want:   []string{},
+		},
+		"null": {
+			source: tftypes.NewValue(tftypes.String, nil),
+			want:   []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := upgradePortForwardingState(t, 0, map[string]tftypes.Value{
+				"name":          tftypes.NewValue(tftypes.String, "test"),
+				"external_port": tftypes.NewValue(tftypes.Number, 443),
+				"internal_port": tftypes.NewValue(tftypes.Number, 8443),
+				"source":        tt.source,
+			})
+
+			if m.Sources.IsNull() {
+				t.Fatal("got null sources, want a set")
+			}
+			if got := setStrings(m.Sources); !slices.Equal(got, tt.want) {
+				t.Errorf("got sources %q, want %q", got, tt.want)
+			}
+			if m.Name.ValueString() != "test" || m.ExternalPortEnd.ValueInt64() != 443 || m.InternalPortEnd.ValueInt64() != 8443 {
+				t.Errorf("got name %s, external_port_end %s and internal_port_end %s, want them carried over",
+					m.Name, m.ExternalPortEnd, m.InternalPortEnd)
+			}
+		})
+	}
+}
+
+func TestPortForwardingUpgradeStateV1(t *testing.T) {
+	tests := map[string]struct {
+		portRange       tftypes.Value
+		wantExternalEnd int64
+	}{
+		"null range":  {portRange: tftypes.NewValue(tftypes.Number, nil), wantExternalEnd: 443},
+		"no range":    {portRange: tftypes.NewValue(tftypes.Number, 0), wantExternalEnd: 443},
+		"single port": {portRange: tftypes.NewValue(tftypes.Number, 1), wantExternalEnd: 443},
+		"port range":  {portRange: tftypes.NewValue(tftypes.Number, 5), wantExternalEnd: 448},
+		"large range": {portRange: tftypes.NewValue(tftypes.Number, 100), wantExternalEnd: 543},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := upgradePortForwardingState(t, 1, map[string]tftypes.Value{
+				"name":          tftypes.NewValue(tftypes.String, "test"),
+				"external_port": tftypes.NewValue(tftypes.Number, 443),
+				"internal_port": tftypes.NewValue(tftypes.Number, 8443),
+				"port_range":    tt.portRange,
+			})
+
+			if got := m.ExternalPortEnd.ValueInt64(); got != tt.wantExternalEnd {
+				t.Errorf("got external_port_end %d, want %d", got, tt.wantExternalEnd)
+			}
+			if got := m.InternalPortEnd.ValueInt64(); got != 8443 {
+				t.Errorf("got internal_port_end %d, want 8443", got)
+			}
+		})
+	}
+}
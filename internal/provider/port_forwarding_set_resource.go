@@ -0,0 +1,494 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &portForwardingSetResource{}
+	_ resource.ResourceWithConfigure      = &portForwardingSetResource{}
+	_ resource.ResourceWithImportState    = &portForwardingSetResource{}
+	_ resource.ResourceWithValidateConfig = &portForwardingSetResource{}
+)
+
+// portForwardingSetResource is the resource implementation.
+type portForwardingSetResource struct {
+	client *livebox.Client
+}
+
+// NewPortForwardingSetResource is a helper function to simplify the provider implementation.
+func NewPortForwardingSetResource() resource.Resource {
+	return &portForwardingSetResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *portForwardingSetResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the resource type name.
+func (r *portForwardingSetResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_port_forwarding_set"
+}
+
+// Schema defines the schema for the resource.
+func (r *portForwardingSetResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Authoritatively manage the port forwarding rules of a Livebox for a given origin and name prefix. " +
+			"Any rule matching the origin and name prefix that is not declared in this resource is deleted.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Identifier of the set, made of its origin and name prefix.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"origin": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(livebox.OriginWebUI),
+				Description: `Origin of the port forwarding rules managed by this resource (e.g. "webui" or "upnp"). Defaults to "webui".`,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "If set, only the rules whose name starts with this prefix are managed by this resource. By default, every rule of the origin is managed.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"rules": schema.MapNestedAttribute{
+				Required:    true,
+				Description: "Port forwarding rules to configure, keyed by name. Names must start with name_prefix.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"protocol": schema.StringAttribute{
+							Required:    true,
+							Description: `Protocol of the port forwarding rule. Must be one of: "tcp", "udp" or "tcp/udp"`,
+							Validators: []validator.String{
+								stringvalidator.OneOf(string(livebox.ProtocolTCP), string(livebox.ProtocolUDP), string(livebox.ProtocolTCPUDP)),
+							},
+						},
+						"external_port": schema.Int64Attribute{
+							Required:    true,
+							Description: "External port of the port forwarding rule.",
+							Validators: []validator.Int64{
+								int64validator.Between(1, 65535),
+							},
+						},
+						"external_port_end": schema.Int64Attribute{
+							Optional:    true,
+							Description: "If set, forwards the range of consecutive external ports from external_port to this port (inclusive).",
+							Validators: []validator.Int64{
+								int64validator.Between(1, 65535),
+							},
+						},
+						"internal_port": schema.Int64Attribute{
+							Required:    true,
+							Description: "Internal port of the port forwarding rule.",
+							Validators: []validator.Int64{
+								int64validator.Between(1, 65535),
+							},
+						},
+						"internal_port_end": schema.Int64Attribute{
+							Optional:    true,
+							Description: "If set, forwards traffic to the range of consecutive internal ports from internal_port to this port (inclusive).",
+							Validators: []validator.Int64{
+								int64validator.Between(1, 65535),
+							},
+						},
+						"destination": schema.StringAttribute{
+							Required:    true,
+							Description: "IP address to forward traffic to.",
+							Validators: []validator.String{
+								ipAddressValidator{},
+							},
+						},
+						"sources": schema.SetAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Description: "Allowed external IP addresses or IPv4 CIDR prefixes to forward traffic from. If not set, traffic from any address is forwarded.",
+							Validators: []validator.Set{
+								setvalidator.ValueStringsAre(sourcePrefixValidator{}),
+							},
+						},
+						"enabled": schema.BoolAttribute{
+							Required:    true,
+							Description: "Whether this port forwarding rule is enabled or not.",
+						},
+						"hairpin_nat": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(true),
+							Description: "Whether hosts of the LAN can reach the forwarded port through the public IP address of the Livebox. Defaults to true.",
+						},
+						"symmetric_snat": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+							Description: "Whether the source address of hairpinned traffic is rewritten so replies go back through the Livebox. Defaults to false.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig ensures every rule of the set is in the scope of the resource.
+// The rules are read as a map value rather than decoded in the model since they may be unknown at this point.
+func (r *portForwardingSetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var namePrefix basetypes.StringValue
+	diags := req.Config.GetAttribute(ctx, path.Root("name_prefix"), &namePrefix)
+	resp.Diagnostics.Append(diags...)
+
+	var rules basetypes.MapValue
+	diags = req.Config.GetAttribute(ctx, path.Root("rules"), &rules)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || namePrefix.IsUnknown() || rules.IsNull() || rules.IsUnknown() {
+		return
+	}
+
+	for name := range rules.Elements() {
+		if !strings.HasPrefix(name, namePrefix.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rules").AtMapKey(name),
+				"Invalid Port Forwarding Rule Name",
+				fmt.Sprintf("The name of the rule %q must start with the name prefix %q.", name, namePrefix.ValueString()),
+			)
+		}
+	}
+}
+
+type portForwardingSetModel struct {
+	ID         basetypes.StringValue                 `tfsdk:"id"`
+	Origin     basetypes.StringValue                 `tfsdk:"origin"`
+	NamePrefix basetypes.StringValue                 `tfsdk:"name_prefix"`
+	Rules      map[string]portForwardingSetRuleModel `tfsdk:"rules"`
+}
+
+type portForwardingSetRuleModel struct {
+	Protocol        basetypes.StringValue `tfsdk:"protocol"`
+	ExternalPort    basetypes.Int64Value  `tfsdk:"external_port"`
+	ExternalPortEnd basetypes.Int64Value  `tfsdk:"external_port_end"`
+	InternalPort    basetypes.Int64Value  `tfsdk:"internal_port"`
+	InternalPortEnd basetypes.Int64Value  `tfsdk:"internal_port_end"`
+	Destination     basetypes.StringValue `tfsdk:"destination"`
+	Sources         basetypes.SetValue    `tfsdk:"sources"`
+	Enabled         basetypes.BoolValue   `tfsdk:"enabled"`
+	HairpinNAT      basetypes.BoolValue   `tfsdk:"hairpin_nat"`
+	SymmetricSNAT   basetypes.BoolValue   `tfsdk:"symmetric_snat"`
+}
+
+// config returns the configuration of the port forwarding rule with the given origin and name described by the model.
+func (m portForwardingSetRuleModel) config(origin, name string) livebox.PortForwardingConfig {
+	return livebox.PortForwardingConfig{
+		Name:            name,
+		Origin:          origin,
+		ExternalPort:    int(m.ExternalPort.ValueInt64()),
+		ExternalPortEnd: int(m.ExternalPortEnd.ValueInt64()),
+		InternalPort:    int(m.InternalPort.ValueInt64()),
+		InternalPortEnd: int(m.InternalPortEnd.ValueInt64()),
+		Protocol:        livebox.Protocol(m.Protocol.ValueString()),
+		Destination:     m.Destination.ValueString(),
+		Source:          normalizeAddressList(joinSources(m.Sources)),
+		Enabled:         m.Enabled.ValueBool(),
+		HairpinNAT:      m.HairpinNAT.ValueBool(),
+		SymmetricSNAT:   m.SymmetricSNAT.ValueBool(),
+	}
+}
+
+// portForwardingSetConflictPath returns the path of the attribute on which conflicts of the given rule are reported.
+func portForwardingSetConflictPath(name string) path.Path {
+	return path.Root("rules").AtMapKey(name).AtName("external_port")
+}
+
+// portForwardingSetRule returns the model of the given rule. Optional attributes that are equivalent to their
+// value in the prior model, if any, are kept as is so that omitting them in the configuration does not produce a diff.
+func portForwardingSetRule(pf livebox.PortForwarding, prior *portForwardingSetRuleModel) portForwardingSetRuleModel {
+	m := portForwardingSetRuleModel{
+		Protocol:        types.StringValue(string(pf.Protocol)),
+		ExternalPort:    types.Int64Value(int64(pf.ExternalPort)),
+		ExternalPortEnd: types.Int64Value(int64(pf.ExternalPortEnd)),
+		InternalPort:    types.Int64Value(int64(pf.InternalPort)),
+		InternalPortEnd: types.Int64Value(int64(pf.InternalPortEnd)),
+		Destination:     types.StringValue(pf.Destination),
+		Sources:         splitSources(pf.Source),
+		Enabled:         types.BoolValue(pf.Enabled),
+		HairpinNAT:      types.BoolValue(pf.HairpinNAT),
+		SymmetricSNAT:   types.BoolValue(pf.SymmetricSNAT),
+	}
+
+	if prior == nil {
+		return m
+	}
+
+	if prior.ExternalPortEnd.IsNull() && pf.ExternalPortEnd == pf.ExternalPort {
+		m.ExternalPortEnd = prior.ExternalPortEnd
+	}
+	if prior.InternalPortEnd.IsNull() && pf.InternalPortEnd == pf.InternalPort {
+		m.InternalPortEnd = prior.InternalPortEnd
+	}
+	if normalizeAddressList(joinSources(prior.Sources)) == normalizeAddressList(pf.Source) {
+		m.Sources = prior.Sources
+	}
+
+	return m
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *portForwardingSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan portForwardingSetModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan)
+	if err != nil {
+		addUpsertPortForwardingError(&resp.Diagnostics, err, portForwardingSetConflictPath,
+			"Error creating port forwarding set",
+			fmt.Sprintf("Could not create port forwarding set, unexpected error: %v", err),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(livebox.PortForwardingID(plan.Origin.ValueString(), plan.NamePrefix.ValueString()))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *portForwardingSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state portForwardingSetModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pfs, err := r.list(ctx, state.Origin.ValueString(), state.NamePrefix.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading port forwarding set",
+			fmt.Sprintf("Could not read state for port forwarding set %q: %v", state.ID.ValueString(), err),
+		)
+		return
+	}
+
+	rules := make(map[string]portForwardingSetRuleModel, len(pfs))
+	for _, pf := range pfs {
+		var prior *portForwardingSetRuleModel
+		if rule, ok := state.Rules[pf.Name]; ok {
+			prior = &rule
+		}
+		rules[pf.Name] = portForwardingSetRule(pf, prior)
+	}
+	state.Rules = rules
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *portForwardingSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan portForwardingSetModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.apply(ctx, plan)
+	if err != nil {
+		addUpsertPortForwardingError(&resp.Diagnostics, err, portForwardingSetConflictPath,
+			"Error updating port forwarding set",
+			fmt.Sprintf("Could not update port forwarding set %q: %v", plan.ID.ValueString(), err),
+		)
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+// Only the rules declared in the set are deleted.
+func (r *portForwardingSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state portForwardingSetModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for name := range state.Rules {
+		if err := r.client.DeletePortForwarding(ctx, state.Origin.ValueString(), name); err != nil {
+			resp.Diagnostics.AddError(
+				"Error deleting port forwarding set",
+				fmt.Sprintf("Could not delete port forward %q: %v", name, err),
+			)
+		}
+	}
+}
+
+// ImportState imports all the port forwarding rules of an origin matching a name prefix into Terraform.
+// The import ID is made of the origin and an optional name prefix separated by an underscore (e.g. "webui" or "webui_lab-").
+func (r *portForwardingSetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	origin, namePrefix, _ := strings.Cut(req.ID, "_")
+	if origin == "" {
+		resp.Diagnostics.AddError(
+			"Error importing port forwarding set",
+			fmt.Sprintf("Could not import port forwarding set %q: the import ID must start with an origin", req.ID),
+		)
+		return
+	}
+
+	state := portForwardingSetModel{
+		ID:         types.StringValue(livebox.PortForwardingID(origin, namePrefix)),
+		Origin:     types.StringValue(origin),
+		NamePrefix: types.StringValue(namePrefix),
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// list returns the port forwarding rules of the given origin whose name starts with the given prefix.
+func (r *portForwardingSetResource) list(ctx context.Context, origin, namePrefix string) ([]livebox.PortForwarding, error) {
+	pfs, err := r.client.ListPortForwardings(ctx, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []livebox.PortForwarding
+	for _, pf := range pfs {
+		if strings.HasPrefix(pf.Name, namePrefix) {
+			out = append(out, pf)
+		}
+	}
+
+	return out, nil
+}
+
+// apply makes the port forwarding rules on the Livebox match the given model. Every declared rule is first checked,
+// so that nothing is deleted if one of them cannot be written. Rules in the scope of the set that are not declared in
+// the model, or whose external ports change, are then deleted, and every declared rule is upserted in name order.
+// Deleting moved rules first lets rules of the set swap ports without conflicting with the previous configuration
+// of each other. If a rule cannot be written anyway, the deleted rules that were not replaced are restored.
+func (r *portForwardingSetResource) apply(ctx context.Context, m portForwardingSetModel) error {
+	origin := m.Origin.ValueString()
+
+	existing, err := r.list(ctx, origin, m.NamePrefix.ValueString())
+	if err != nil {
+		return fmt.Errorf("list port forwardings: %w", err)
+	}
+
+	names := make([]string, 0, len(m.Rules))
+	for name := range m.Rules {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	cfgs := make([]livebox.PortForwardingConfig, 0, len(names))
+	for _, name := range names {
+		cfgs = append(cfgs, m.Rules[name].config(origin, name))
+	}
+
+	replacedIDs := make([]string, 0, len(existing))
+	for _, pf := range existing {
+		replacedIDs = append(replacedIDs, pf.ID)
+	}
+
+	if err = r.client.CheckPortForwardings(ctx, cfgs, replacedIDs); err != nil {
+		return err
+	}
+
+	var deleted []livebox.PortForwarding
+	for _, pf := range existing {
+		if rule, ok := m.Rules[pf.Name]; ok && rule.sameExternalPorts(pf) {
+			continue
+		}
+
+		if err = r.client.DeletePortForwarding(ctx, pf.Origin, pf.Name); err != nil {
+			err = fmt.Errorf("delete port forward %q: %w", pf.Name, err)
+			return errors.Join(err, r.restore(ctx, deleted, nil))
+		}
+		deleted = append(deleted, pf)
+	}
+
+	var written []string
+	for _, cfg := range cfgs {
+		if err = r.client.UpsertPortForwarding(ctx, cfg); err != nil {
+			err = fmt.Errorf("upsert port forward %q: %w", cfg.Name, err)
+			return errors.Join(err, r.restore(ctx, deleted, written))
+		}
+		written = append(written, cfg.Name)
+	}
+
+	return nil
+}
+
+// restore writes back the given deleted rules, except the ones that were replaced by a rule of the same name.
+func (r *portForwardingSetResource) restore(ctx context.Context, deleted []livebox.PortForwarding, written []string) error {
+	var errs []error
+	for _, pf := range deleted {
+		if slices.Contains(written, pf.Name) {
+			continue
+		}
+
+		if err := r.client.UpsertPortForwarding(ctx, pf.Config()); err != nil {
+			errs = append(errs, fmt.Errorf("restore port forward %q: %w", pf.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sameExternalPorts reports whether the rule described by the model forwards the same protocol
+// and external ports as the given existing rule.
+func (m portForwardingSetRuleModel) sameExternalPorts(pf livebox.PortForwarding) bool {
+	externalEnd := m.ExternalPortEnd.ValueInt64()
+	if m.ExternalPortEnd.IsNull() {
+		externalEnd = m.ExternalPort.ValueInt64()
+	}
+
+	return string(pf.Protocol) == m.Protocol.ValueString() &&
+		int64(pf.ExternalPort) == m.ExternalPort.ValueInt64() &&
+		int64(pf.ExternalPortEnd) == externalEnd
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPortForwardingSetResourceValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	r := NewPortForwardingSetResource().(*portForwardingSetResource)
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	rulesType := objType.AttributeTypes["rules"].(tftypes.Map)
+	ruleType := rulesType.ElementType
+
+	tests := map[string]struct {
+		rules     tftypes.Value
+		wantError bool
+	}{
+		"unknown rules": {
+			rules: tftypes.NewValue(rulesType, tftypes.UnknownValue),
+		},
+		"unknown rule": {
+			rules: tftypes.NewValue(rulesType, map[string]tftypes.Value{
+				"lab-ssh": tftypes.NewValue(ruleType, tftypes.UnknownValue),
+			}),
+		},
+		"rule out of scope": {
+			rules: tftypes.NewValue(rulesType, map[string]tftypes.Value{
+				"ssh": tftypes.NewValue(ruleType, tftypes.UnknownValue),
+			}),
+			wantError: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			attrs := map[string]tftypes.Value{}
+			for attr, typ := range objType.AttributeTypes {
+				attrs[attr] = tftypes.NewValue(typ, nil)
+			}
+			attrs["name_prefix"] = tftypes.NewValue(tftypes.String, "lab-")
+			attrs["rules"] = tt.rules
+
+			req := resource.ValidateConfigRequest{
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw:    tftypes.NewValue(objType, attrs),
+				},
+			}
+			var resp resource.ValidateConfigResponse
+
+			r.ValidateConfig(ctx, req, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantError {
+				t.Errorf("got errors %v, want error: %t", resp.Diagnostics, tt.wantError)
+			}
+		})
+	}
+}
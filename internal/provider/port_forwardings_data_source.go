@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &portForwardingsDataSource{}
+	_ datasource.DataSourceWithConfigure = &portForwardingsDataSource{}
+)
+
+// portForwardingsDataSource is the data source implementation.
+type portForwardingsDataSource struct {
+	client *livebox.Client
+}
+
+// NewPortForwardingsDataSource is a helper function to simplify the provider implementation.
+func NewPortForwardingsDataSource() datasource.DataSource {
+	return &portForwardingsDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *portForwardingsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*livebox.Client)
+}
+
+// Metadata returns the data source type name.
+func (d *portForwardingsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_port_forwardings"
+}
+
+// Schema defines the schema for the data source.
+func (d *portForwardingsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the port forwarding rules configured on a Livebox.",
+		Attributes: map[string]schema.Attribute{
+			"origin": schema.StringAttribute{
+				Optional:    true,
+				Description: `If set, only returns the rules created by this origin (e.g. "webui" or "upnp"). Otherwise, the rules of every origin are returned.`,
+			},
+			"protocol": schema.StringAttribute{
+				Optional:    true,
+				Description: `If set, only returns the rules using this protocol. Must be one of: "tcp", "udp" or "tcp/udp"`,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(livebox.ProtocolTCP), string(livebox.ProtocolUDP), string(livebox.ProtocolTCPUDP)),
+				},
+			},
+			"destination": schema.StringAttribute{
+				Optional:    true,
+				Description: "If set, only returns the rules forwarding traffic to this IP address.",
+				Validators: []validator.String{
+					ipAddressValidator{},
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If set, only returns the rules that are enabled or disabled.",
+			},
+			"rules": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Port forwarding rules matching the filters, sorted by ID.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the port forwarding rule on the Livebox.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the port forwarding rule.",
+						},
+						"origin": schema.StringAttribute{
+							Computed:    true,
+							Description: "Origin of the port forwarding rule.",
+						},
+						"protocol": schema.StringAttribute{
+							Computed:    true,
+							Description: "Protocol of the port forwarding rule.",
+						},
+						"external_port": schema.Int64Attribute{
+							Computed:    true,
+							Description: "External port of the port forwarding rule.",
+						},
+						"internal_port": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Internal port of the port forwarding rule.",
+						},
+						"external_port_end": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Last external port of the range forwarded, equal to external_port when a single port is forwarded.",
+						},
+						"internal_port_end": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Last internal port of the range traffic is forwarded to, equal to internal_port when forwarding to a single port.",
+						},
+						"destination": schema.StringAttribute{
+							Computed:    true,
+							Description: "IP address traffic is forwarded to.",
+						},
+						"sources": schema.SetAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "Allowed external IP addresses or IPv4 CIDR prefixes to forward traffic from.",
+						},
+						"enabled": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether this port forwarding rule is enabled or not.",
+						},
+						"hairpin_nat": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether hosts of the LAN can reach the forwarded port through the public IP address of the Livebox.",
+						},
+						"symmetric_snat": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the source address of hairpinned traffic is rewritten so replies go back through the Livebox.",
+						},
+						"upnpv1_compat": schema.BoolAttribute{
+							Computed:    true,
+							Description: "Whether the rule is compatible with UPnP IGD v1 clients.",
+						},
+						"lease_duration": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Duration of the rule in seconds, 0 meaning it never expires.",
+						},
+						"status": schema.StringAttribute{
+							Computed:    true,
+							Description: "Status of the rule as reported by the Livebox.",
+						},
+						"destination_mac_address": schema.StringAttribute{
+							Computed:    true,
+							Description: "MAC address of the device traffic is forwarded to.",
+						},
+						"source_interface": schema.StringAttribute{
+							Computed:    true,
+							Description: "Interface of the Livebox the rule applies to.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type portForwardingsDataSourceModel struct {
+	Origin      basetypes.StringValue                `tfsdk:"origin"`
+	Protocol    basetypes.StringValue                `tfsdk:"protocol"`
+	Destination basetypes.StringValue                `tfsdk:"destination"`
+	Enabled     basetypes.BoolValue                  `tfsdk:"enabled"`
+	Rules       []portForwardingsDataSourceRuleModel `tfsdk:"rules"`
+}
+
+type portForwardingsDataSourceRuleModel struct {
+	ID              basetypes.StringValue `tfsdk:"id"`
+	Name            basetypes.StringValue `tfsdk:"name"`
+	Origin          basetypes.StringValue `tfsdk:"origin"`
+	Protocol        basetypes.StringValue `tfsdk:"protocol"`
+	ExternalPort    basetypes.Int64Value  `tfsdk:"external_port"`
+	ExternalPortEnd basetypes.Int64Value  `tfsdk:"external_port_end"`
+	InternalPort    basetypes.Int64Value  `tfsdk:"internal_port"`
+	InternalPortEnd basetypes.Int64Value  `tfsdk:"internal_port_end"`
+	Destination     basetypes.StringValue `tfsdk:"destination"`
+	Sources         basetypes.SetValue    `tfsdk:"sources"`
+	Enabled         basetypes.BoolValue   `tfsdk:"enabled"`
+
+	HairpinNAT            basetypes.BoolValue   `tfsdk:"hairpin_nat"`
+	SymmetricSNAT         basetypes.BoolValue   `tfsdk:"symmetric_snat"`
+	UPnPV1Compat          basetypes.BoolValue   `tfsdk:"upnpv1_compat"`
+	LeaseDuration         basetypes.Int64Value  `tfsdk:"lease_duration"`
+	Status                basetypes.StringValue `tfsdk:"status"`
+	DestinationMACAddress basetypes.StringValue `tfsdk:"destination_mac_address"`
+	SourceInterface       basetypes.StringValue `tfsdk:"source_interface"`
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *portForwardingsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state portForwardingsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pfs, err := d.client.ListPortForwardings(ctx, state.Origin.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error listing port forwards",
+			fmt.Sprintf("Could not list port forwards: %v", err),
+		)
+		return
+	}
+
+	slices.SortFunc(pfs, func(a, b livebox.PortForwarding) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	state.Rules = make([]portForwardingsDataSourceRuleModel, 0, len(pfs))
+	for _, pf := range pfs {
+		if !state.Protocol.IsNull() && string(pf.Protocol) != state.Protocol.ValueString() {
+			continue
+		}
+		if !state.Destination.IsNull() && pf.Destination != state.Destination.ValueString() {
+			continue
+		}
+		if !state.Enabled.IsNull() && pf.Enabled != state.Enabled.ValueBool() {
+			continue
+		}
+
+		state.Rules = append(state.Rules, portForwardingsDataSourceRuleModel{
+			ID:              types.StringValue(pf.ID),
+			Name:            types.StringValue(pf.Name),
+			Origin:          types.StringValue(pf.Origin),
+			Protocol:        types.StringValue(string(pf.Protocol)),
+			ExternalPort:    types.Int64Value(int64(pf.ExternalPort)),
+			ExternalPortEnd: types.Int64Value(int64(pf.ExternalPortEnd)),
+			InternalPort:    types.Int64Value(int64(pf.InternalPort)),
+			InternalPortEnd: types.Int64Value(int64(pf.InternalPortEnd)),
+			Destination:     types.StringValue(pf.Destination),
+			Sources:         splitSources(pf.Source),
+			Enabled:         types.BoolValue(pf.Enabled),
+
+			HairpinNAT:            types.BoolValue(pf.HairpinNAT),
+			SymmetricSNAT:         types.BoolValue(pf.SymmetricSNAT),
+			UPnPV1Compat:          types.BoolValue(pf.UPnPV1Compat),
+			LeaseDuration:         types.Int64Value(int64(pf.LeaseDuration)),
+			Status:                types.StringValue(pf.Status),
+			DestinationMACAddress: types.StringValue(pf.DestinationMACAddress),
+			SourceInterface:       types.StringValue(pf.SourceInterface),
+		})
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -35,7 +35,7 @@ func (l *Livebox) Metadata(_ context.Context, _ provider.MetadataRequest, resp *
 
 func (l *Livebox) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "A terraform provider to interact with a Livebox. It currently only supports configuring port forwarding rules.",
+		Description: "A terraform provider to interact with a Livebox. It currently supports configuring port forwarding rules, IPv6 pinholes, the DMZ host, the firewall, the LAN and DHCP static leases.",
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
 				Optional:    true,
@@ -124,7 +124,7 @@ func (l *Livebox) Configure(ctx context.Context, req provider.ConfigureRequest,
 
 	tflog.Debug(ctx, "Creating Livebox client")
 
-	client, err := livebox.NewClient(host, password)
+	client, err := livebox.NewClient(ctx, host, password)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Livebox Client",
@@ -142,11 +142,22 @@ func (l *Livebox) Configure(ctx context.Context, req provider.ConfigureRequest,
 }
 
 func (l *Livebox) DataSources(_ context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewPortForwardingsDataSource,
+		NewFirewallRulesDataSource,
+		NewDevicesDataSource,
+	}
 }
 
 func (l *Livebox) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewPortForwardingResource,
+		NewPortForwardingSetResource,
+		NewIPv6PinholeResource,
+		NewDMZResource,
+		NewFirewallResource,
+		NewFirewallRuleResource,
+		NewDHCPStaticLeaseResource,
+		NewLANResource,
 	}
 }
@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/skwair/terraform-provider-livebox/livebox"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ validator.String = ipAddressValidator{}
+	_ validator.String = sourcePrefixValidator{}
+	_ validator.String = ipv4AddressValidator{}
+	_ validator.String = ipv6AddressValidator{}
+	_ validator.String = ipv6PrefixValidator{}
+	_ validator.String = protocolValidator{}
+	_ validator.String = macAddressValidator{}
+)
+
+// ipAddressValidator validates that a string attribute is a valid IP address.
+type ipAddressValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v ipAddressValidator) Description(_ context.Context) string {
+	return "value must be a valid IP address"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v ipAddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v ipAddressValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if net.ParseIP(req.ConfigValue.ValueString()) == nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid IP Address",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// sourcePrefixValidator validates that a string attribute is a valid IP address or IPv4 CIDR prefix.
+type sourcePrefixValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v sourcePrefixValidator) Description(_ context.Context) string {
+	return "value must be a valid IP address or IPv4 CIDR prefix"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v sourcePrefixValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v sourcePrefixValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !livebox.IsValidSourcePrefix(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Source Prefix",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// ipv4AddressValidator validates that a string attribute is a valid IPv4 address.
+type ipv4AddressValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v ipv4AddressValidator) Description(_ context.Context) string {
+	return "value must be a valid IPv4 address"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v ipv4AddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v ipv4AddressValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if ip, err := netip.ParseAddr(req.ConfigValue.ValueString()); err != nil || !ip.Is4() {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid IPv4 Address",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// ipv6AddressValidator validates that a string attribute is a valid IPv6 address.
+type ipv6AddressValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v ipv6AddressValidator) Description(_ context.Context) string {
+	return "value must be a valid IPv6 address"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v ipv6AddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v ipv6AddressValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if ip, err := netip.ParseAddr(req.ConfigValue.ValueString()); err != nil || !ip.Is6() || ip.Is4In6() {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid IPv6 Address",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// ipv6PrefixValidator validates that a string attribute is a valid IPv6 address or CIDR prefix.
+type ipv6PrefixValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v ipv6PrefixValidator) Description(_ context.Context) string {
+	return "value must be a valid IPv6 address or CIDR prefix"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v ipv6PrefixValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v ipv6PrefixValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || req.ConfigValue.ValueString() == "" {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if livebox.IsValidPrefix(value, 6) {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid IPv6 Prefix",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+	)
+}
+
+// protocolValidator validates that a string attribute is a protocol name or an IP protocol number,
+// written the way the Livebox reports it so that it does not produce a diff after being read back.
+type protocolValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v protocolValidator) Description(_ context.Context) string {
+	return `value must be one of "tcp", "udp", "tcp/udp", "icmp", "icmpv6", "gre", "esp", "ah" or another IP protocol number between 0 and 255`
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v protocolValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v protocolValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	p, err := livebox.ParseProtocol(value)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Protocol",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+		)
+		return
+	}
+
+	if string(p) != value {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Protocol",
+			fmt.Sprintf("Attribute %s must be written %q, got: %q", req.Path, p, value),
+		)
+	}
+}
+
+// macAddressValidator validates that a string attribute is a valid MAC address.
+type macAddressValidator struct{}
+
+// Description describes the validation in plain text formatting.
+func (v macAddressValidator) Description(_ context.Context) string {
+	return "value must be a valid MAC address"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v macAddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v macAddressValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if mac, err := net.ParseMAC(req.ConfigValue.ValueString()); err != nil || len(mac) != 6 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid MAC Address",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
@@ -1,18 +1,30 @@
 package livebox
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
+	"sync"
 )
 
 // Client used to interact with the Livebox API.
-// Note that for now, this client is not designed to be long-lived.
 // It uses the same cookie-based session mechanism as the official web interface which keeps active connections for
-// roughly 5 minutes.
+// roughly 5 minutes. When a session expires, the client transparently logs in again and retries the failed request,
+// so it can safely be used for long-running operations and shared between goroutines.
 type Client struct {
-	host  string
+	password string
+
+	// hostMu guards host, which changes when the IP address of the Livebox on the LAN changes.
+	// It is separate from mu since the host is also used while logging in.
+	hostMu sync.Mutex
+	host   string
+
+	mu    sync.Mutex
 	token string
 
 	httpClient *http.Client
@@ -22,14 +34,16 @@ type Client struct {
 // The host parameter must contain one of the following schemes: http, https.
 // Of course, https is strongly recommended even if the Livebox serves a self-signed certificate,
 // at least the connection will be encrypted.
-func NewClient(host, password string) (*Client, error) {
+// The given context is only used to log in to the Livebox, it is not retained by the client.
+func NewClient(ctx context.Context, host, password string) (*Client, error) {
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c := &Client{
-		host: host,
+		host:     host,
+		password: password,
 		httpClient: &http.Client{
 			Jar: jar,
 			Transport: &cookieNamePatcher{
@@ -40,25 +54,30 @@ func NewClient(host, password string) (*Client, error) {
 		},
 	}
 
-	if err := c.login(password); err != nil {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.login(ctx); err != nil {
 		return nil, err
 	}
 
 	return c, nil
 }
 
-func (c *Client) login(password string) error {
+// login creates a new session on the Livebox and stores its context ID.
+// It must be called with c.mu held.
+func (c *Client) login(ctx context.Context) error {
 	payload := &apiRequest{
 		Service: "sah.Device.Information",
 		Method:  "createContext",
 		Parameters: map[string]any{
 			"applicationName": "webui",
 			"username":        "admin",
-			"password":        password,
+			"password":        c.password,
 		},
 	}
 
-	resp, err := c.doAuthReq(payload)
+	resp, err := c.doAuthReq(ctx, payload)
 	if err != nil {
 		return err
 	}
@@ -68,12 +87,71 @@ func (c *Client) login(password string) error {
 		Data struct {
 			ContextID string `json:"contextID"`
 		} `json:"data"`
+		Errors []APIError `json:"errors"`
 	}
 	if err = json.NewDecoder(resp.Body).Decode(&lr); err != nil {
 		return err
 	}
 
+	if len(lr.Errors) > 0 {
+		return apiErrors(lr.Errors)
+	}
+
+	if lr.Data.ContextID == "" {
+		return fmt.Errorf("no context ID returned, check the password: %w", ErrUnauthorized)
+	}
+
 	c.token = lr.Data.ContextID
 
 	return nil
 }
+
+// relogin logs in again, unless another goroutine already renewed the session
+// since the given (expired) token was used.
+func (c *Client) relogin(ctx context.Context, expiredToken string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != expiredToken {
+		return nil
+	}
+
+	return c.login(ctx)
+}
+
+// currentToken returns the context ID of the current session.
+func (c *Client) currentToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token
+}
+
+// currentHost returns the URL of the Livebox, without the API path.
+func (c *Client) currentHost() string {
+	c.hostMu.Lock()
+	defer c.hostMu.Unlock()
+
+	return c.host
+}
+
+// moveHost updates the URL of the Livebox after its IP address on the LAN changed from oldAddr to newAddr.
+// The URL is only changed if it points at the old address, hosts such as a domain name or the WAN
+// address are left as they are.
+func (c *Client) moveHost(oldAddr, newAddr string) {
+	c.hostMu.Lock()
+	defer c.hostMu.Unlock()
+
+	u, err := url.Parse(c.host)
+	if err != nil || u.Hostname() != oldAddr {
+		return
+	}
+
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(newAddr, port)
+	} else {
+		u.Host = newAddr
+	}
+
+	c.host = u.String()
+}
@@ -0,0 +1,27 @@
+package livebox
+
+import (
+	"testing"
+)
+
+func TestClientMoveHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "https://192.168.1.1", want: "https://192.168.2.1"},
+		{host: "https://192.168.1.1:8443", want: "https://192.168.2.1:8443"},
+		{host: "http://192.168.1.1/", want: "http://192.168.2.1/"},
+		{host: "https://livebox.home", want: "https://livebox.home"},
+		{host: "https://203.0.113.7", want: "https://203.0.113.7"},
+	}
+
+	for _, tt := range tests {
+		c := &Client{host: tt.host}
+		c.moveHost("192.168.1.1", "192.168.2.1")
+
+		if got := c.currentHost(); got != tt.want {
+			t.Errorf("moveHost on %q: got %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package livebox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Device interface types reported by the Livebox:
+const (
+	InterfaceTypeEthernet = "ethernet"
+	InterfaceTypeWiFi24   = "wifi_2.4ghz"
+	InterfaceTypeWiFi5    = "wifi_5ghz"
+	InterfaceTypeWiFi6    = "wifi_6ghz"
+)
+
+// Device describes a host known by the Livebox.
+type Device struct {
+	MACAddress string
+	Name       string
+	// Hostname is the name the device advertised through DHCP, if any.
+	Hostname string
+	// IPAddress is the main IPv4 address of the device.
+	IPAddress     string
+	IPv4Addresses []string
+	IPv6Addresses []string
+	// InterfaceType is the kind of link the device is connected with, one of the InterfaceType constants,
+	// or an empty string if the Livebox does not report it.
+	InterfaceType string
+	Active        bool
+	// LastSeen is the last time the device was connected to the Livebox, or the zero time if unknown.
+	LastSeen time.Time
+}
+
+type getDeviceResp struct {
+	Key                    string    `json:"Key"`
+	PhysAddress            string    `json:"PhysAddress"`
+	Name                   string    `json:"Name"`
+	Names                  []devName `json:"Names"`
+	IPAddress              string    `json:"IPAddress"`
+	IPv4Address            []devAddr `json:"IPv4Address"`
+	IPv6Address            []devAddr `json:"IPv6Address"`
+	Layer2Interface        string    `json:"Layer2Interface"`
+	OperatingFrequencyBand string    `json:"OperatingFrequencyBand"`
+	Active                 bool      `json:"Active"`
+	LastConnection         string    `json:"LastConnection"`
+}
+
+type devName struct {
+	Name   string `json:"Name"`
+	Source string `json:"Source"`
+}
+
+type devAddr struct {
+	Address string `json:"Address"`
+}
+
+// interfaceType returns the kind of link the device is connected with.
+func (r getDeviceResp) interfaceType() string {
+	switch strings.ToLower(r.OperatingFrequencyBand) {
+	case "2.4ghz":
+		return InterfaceTypeWiFi24
+	case "5ghz":
+		return InterfaceTypeWiFi5
+	case "6ghz":
+		return InterfaceTypeWiFi6
+	}
+
+	if strings.HasPrefix(strings.ToLower(r.Layer2Interface), "eth") {
+		return InterfaceTypeEthernet
+	}
+
+	return ""
+}
+
+// hostname returns the name the device advertised through DHCP, if any.
+func (r getDeviceResp) hostname() string {
+	for _, n := range r.Names {
+		if n.Source == "dhcp" {
+			return n.Name
+		}
+	}
+
+	return ""
+}
+
+// lastSeen returns the last time the device was connected, or the zero time if the Livebox does not know it.
+func (r getDeviceResp) lastSeen() time.Time {
+	// Unknown timestamps are reported as "0001-01-01T00:00:00Z" or left empty; the latter fails to parse.
+	t, err := time.Parse(time.RFC3339, r.LastConnection)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+func addresses(addrs []devAddr) []string {
+	out := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		out = append(out, a.Address)
+	}
+
+	return out
+}
+
+// ListDevices returns all the hosts of the LAN known by the Livebox, whether they are currently connected or not.
+func (c *Client) ListDevices(ctx context.Context) ([]Device, error) {
+	payload := &apiRequest{
+		Service: "Devices",
+		Method:  "get",
+		Parameters: map[string]any{
+			"expression": "lan and not self",
+		},
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	var dr []getDeviceResp
+	if err = json.Unmarshal(data, &dr); err != nil {
+		return nil, fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	out := make([]Device, 0, len(dr))
+	for _, raw := range dr {
+		d := Device{
+			MACAddress:    strings.ToLower(raw.PhysAddress),
+			Name:          raw.Name,
+			Hostname:      raw.hostname(),
+			IPAddress:     raw.IPAddress,
+			IPv4Addresses: addresses(raw.IPv4Address),
+			IPv6Addresses: addresses(raw.IPv6Address),
+			InterfaceType: raw.interfaceType(),
+			Active:        raw.Active,
+			LastSeen:      raw.lastSeen(),
+		}
+		out = append(out, d)
+	}
+
+	return out, nil
+}
+
+// GetDevice returns the device matching the given MAC address, or name or hostname (case-insensitively), if found.
+// If there is no such device, the returned error matches ErrNotFound. If several devices share the given name,
+// an error listing their MAC addresses is returned rather than picking one of them.
+// Like GetPortForwarding, this method lists all the devices and filters the result.
+func (c *Client) GetDevice(ctx context.Context, macOrName string) (*Device, error) {
+	devices, err := c.ListDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list devices: %w", err)
+	}
+
+	if mac, err := net.ParseMAC(macOrName); err == nil {
+		for _, d := range devices {
+			if d.MACAddress == mac.String() {
+				return &d, nil
+			}
+		}
+
+		return nil, fmt.Errorf("device %q: %w", macOrName, ErrNotFound)
+	}
+
+	var matches []Device
+	for _, d := range devices {
+		if d.MatchesName(macOrName) {
+			matches = append(matches, d)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("device %q: %w", macOrName, ErrNotFound)
+	case 1:
+		return &matches[0], nil
+	default:
+		macs := make([]string, 0, len(matches))
+		for _, d := range matches {
+			macs = append(macs, d.MACAddress)
+		}
+
+		return nil, fmt.Errorf("device %q: several devices have this name (%s), use a MAC address instead: %w",
+			macOrName, strings.Join(macs, ", "), ErrConflict)
+	}
+}
+
+// MatchesName reports whether the name or hostname of the device is equal to the given name, case-insensitively.
+func (d Device) MatchesName(name string) bool {
+	return strings.EqualFold(d.Name, name) || strings.EqualFold(d.Hostname, name)
+}
@@ -0,0 +1,247 @@
+package livebox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// dhcpPoolService is the service of the Livebox API managing the DHCP pool of the LAN.
+const dhcpPoolService = "DHCPv4.Server.Pool.default"
+
+// DHCPPool describes the DHCP pool of the LAN, from which the Livebox leases IPv4 addresses.
+type DHCPPool struct {
+	Enabled bool
+	// MinAddress and MaxAddress are the first and last addresses of the pool (inclusive).
+	MinAddress string
+	MaxAddress string
+	SubnetMask string
+	// LeaseTime is the duration of the leases in seconds.
+	LeaseTime int
+}
+
+type getDHCPPoolResp struct {
+	Enable     bool   `json:"Enable"`
+	MinAddress string `json:"MinAddress"`
+	MaxAddress string `json:"MaxAddress"`
+	SubnetMask string `json:"SubnetMask"`
+	LeaseTime  int    `json:"LeaseTime"`
+}
+
+// GetDHCPPool returns the DHCP pool of the LAN.
+func (c *Client) GetDHCPPool(ctx context.Context) (*DHCPPool, error) {
+	payload := &apiRequest{
+		Service:    dhcpPoolService,
+		Method:     "get",
+		Parameters: map[string]any{},
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	var pr getDHCPPoolResp
+	if err = json.Unmarshal(data, &pr); err != nil {
+		return nil, fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	pool := DHCPPool{
+		Enabled:    pr.Enable,
+		MinAddress: pr.MinAddress,
+		MaxAddress: pr.MaxAddress,
+		SubnetMask: pr.SubnetMask,
+		LeaseTime:  pr.LeaseTime,
+	}
+
+	return &pool, nil
+}
+
+// Contains reports whether the given IPv4 address is inside the pool.
+func (p *DHCPPool) Contains(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	minAddr, err := netip.ParseAddr(p.MinAddress)
+	if err != nil {
+		return false
+	}
+
+	maxAddr, err := netip.ParseAddr(p.MaxAddress)
+	if err != nil {
+		return false
+	}
+
+	return addr.Compare(minAddr) >= 0 && addr.Compare(maxAddr) <= 0
+}
+
+// StaticLease describes an IPv4 address reserved by the DHCP server for the device with the given MAC address.
+type StaticLease struct {
+	MACAddress string
+	IPAddress  string
+}
+
+type getStaticLeaseResp struct {
+	MACAddress string `json:"MACAddress"`
+	IPAddress  string `json:"IPAddress"`
+}
+
+// ListStaticLeases returns all the static leases of the DHCP pool of the LAN.
+// MAC addresses are returned in lower case.
+func (c *Client) ListStaticLeases(ctx context.Context) ([]StaticLease, error) {
+	payload := &apiRequest{
+		Service:    dhcpPoolService,
+		Method:     "getStaticLeases",
+		Parameters: map[string]any{},
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	var slr []getStaticLeaseResp
+	if err = json.Unmarshal(data, &slr); err != nil {
+		return nil, fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	out := make([]StaticLease, 0, len(slr))
+	for _, raw := range slr {
+		out = append(out, StaticLease{
+			MACAddress: strings.ToLower(raw.MACAddress),
+			IPAddress:  raw.IPAddress,
+		})
+	}
+
+	return out, nil
+}
+
+// GetStaticLease returns the static lease of the device with the given MAC address, if found.
+// If there is no such lease, the returned error matches ErrNotFound.
+// Like GetPortForwarding, this method lists all the static leases and filters the result.
+func (c *Client) GetStaticLease(ctx context.Context, macAddress string) (*StaticLease, error) {
+	mac, err := net.ParseMAC(macAddress)
+	if err != nil {
+		return nil, fmt.Errorf("parse MAC address: %w", err)
+	}
+
+	leases, err := c.ListStaticLeases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list static leases: %w", err)
+	}
+
+	for _, l := range leases {
+		if l.MACAddress == mac.String() {
+			return &l, nil
+		}
+	}
+
+	return nil, fmt.Errorf("static lease %q: %w", macAddress, ErrNotFound)
+}
+
+// validate performs some basic validation on a static lease.
+func (l StaticLease) validate() error {
+	if _, err := net.ParseMAC(l.MACAddress); err != nil {
+		return errors.New("invalid MAC address")
+	}
+
+	if ip := net.ParseIP(l.IPAddress); ip == nil || ip.To4() == nil {
+		return errors.New("invalid IP address; must be a valid IPv4 address")
+	}
+
+	return nil
+}
+
+// UpsertStaticLease upserts the given static lease.
+// The IP address must be inside the DHCP pool of the Livebox. The Livebox cannot update a static lease in place,
+// so any existing lease for the same MAC address is deleted first.
+// If the new lease is then rejected, the previous one is restored.
+func (c *Client) UpsertStaticLease(ctx context.Context, lease StaticLease) error {
+	if err := lease.validate(); err != nil {
+		return fmt.Errorf("validate configuration: %w", err)
+	}
+
+	// The pool is checked before touching the existing lease, since the Livebox rejects leases outside of it.
+	pool, err := c.GetDHCPPool(ctx)
+	if err != nil {
+		return fmt.Errorf("get DHCP pool: %w", err)
+	}
+
+	if !pool.Contains(lease.IPAddress) {
+		return fmt.Errorf("invalid IP address; %q is outside of the DHCP pool, which ranges from %s to %s", lease.IPAddress, pool.MinAddress, pool.MaxAddress)
+	}
+
+	prev, err := c.GetStaticLease(ctx, lease.MACAddress)
+	switch {
+	case err == nil:
+		if err = c.DeleteStaticLease(ctx, prev.MACAddress); err != nil {
+			return fmt.Errorf("delete existing static lease: %w", err)
+		}
+	case errors.Is(err, ErrNotFound):
+		prev = nil
+	default:
+		return fmt.Errorf("get existing static lease: %w", err)
+	}
+
+	if err = c.addStaticLease(ctx, lease); err != nil {
+		if prev != nil {
+			if restoreErr := c.addStaticLease(ctx, *prev); restoreErr != nil {
+				return errors.Join(err, fmt.Errorf("restore previous static lease: %w", restoreErr))
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) addStaticLease(ctx context.Context, lease StaticLease) error {
+	mac, err := net.ParseMAC(lease.MACAddress)
+	if err != nil {
+		return fmt.Errorf("parse MAC address: %w", err)
+	}
+
+	payload := &apiRequest{
+		Service: dhcpPoolService,
+		Method:  "addStaticLease",
+		Parameters: map[string]any{
+			"MACAddress": mac.String(),
+			"IPAddress":  lease.IPAddress,
+		},
+	}
+
+	if _, err = c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStaticLease deletes the static lease of the device with the given MAC address.
+func (c *Client) DeleteStaticLease(ctx context.Context, macAddress string) error {
+	mac, err := net.ParseMAC(macAddress)
+	if err != nil {
+		return fmt.Errorf("parse MAC address: %w", err)
+	}
+
+	payload := &apiRequest{
+		Service: dhcpPoolService,
+		Method:  "deleteStaticLease",
+		Parameters: map[string]any{
+			"MACAddress": mac.String(),
+		},
+	}
+
+	if _, err = c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package livebox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// DMZID is the ID of the DMZ configured from the web interface of the Livebox.
+// The Livebox supports a single DMZ host, which always uses this ID.
+const DMZID = "webui"
+
+// DMZ describes the DMZ host of the Livebox, to which all unsolicited inbound IPv4 traffic
+// that does not match a port forwarding rule is forwarded.
+type DMZ struct {
+	ID          string
+	Destination string
+	Source      string
+	Enabled     bool
+	// Status of the DMZ as reported by the Livebox (e.g. "Enabled", "Disabled" or "Error").
+	Status string
+}
+
+type getDMZResp struct {
+	SourceInterface      string `json:"SourceInterface"`
+	DestinationIPAddress string `json:"DestinationIPAddress"`
+	SourcePrefix         string `json:"SourcePrefix"`
+	Status               string `json:"Status"`
+	Enable               bool   `json:"Enable"`
+}
+
+// GetDMZ returns the DMZ host currently configured on the Livebox.
+// If there is none, the returned error matches ErrNotFound.
+func (c *Client) GetDMZ(ctx context.Context) (*DMZ, error) {
+	payload := &apiRequest{
+		Service:    "Firewall",
+		Method:     "getDMZ",
+		Parameters: map[string]any{},
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	var dr map[string]getDMZResp
+	if err = json.Unmarshal(data, &dr); err != nil {
+		return nil, fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	raw, ok := dr[DMZID]
+	if !ok {
+		return nil, fmt.Errorf("DMZ %q: %w", DMZID, ErrNotFound)
+	}
+
+	dmz := DMZ{
+		ID:          DMZID,
+		Destination: raw.DestinationIPAddress,
+		Source:      raw.SourcePrefix,
+		Enabled:     raw.Enable,
+		Status:      raw.Status,
+	}
+
+	return &dmz, nil
+}
+
+// DMZConfig configures the DMZ host of the Livebox.
+// Source is an optional IP address or IPv4 CIDR prefix restricting which hosts can reach the DMZ.
+type DMZConfig struct {
+	Destination string
+	Source      string
+	Enabled     bool
+}
+
+// validate performs some basic validation on a DMZ configuration.
+func (c DMZConfig) validate() error {
+	if dest := net.ParseIP(c.Destination); dest == nil || dest.To4() == nil {
+		return errors.New("invalid destination; must be a valid IPv4 address")
+	}
+
+	if c.Source != "" && !IsValidSourcePrefix(c.Source) {
+		return errors.New("invalid source; must be a valid IP address or IPv4 CIDR prefix")
+	}
+
+	return nil
+}
+
+// SetDMZ configures the DMZ host of the Livebox, replacing any existing one.
+func (c *Client) SetDMZ(ctx context.Context, cfg DMZConfig) error {
+	if err := cfg.validate(); err != nil {
+		return fmt.Errorf("validate configuration: %w", err)
+	}
+
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  "setDMZ",
+		Parameters: map[string]any{
+			"id":                   DMZID,
+			"sourceInterface":      "data",
+			"destinationIPAddress": cfg.Destination,
+			"sourcePrefix":         cfg.Source,
+			"enable":               cfg.Enabled,
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteDMZ removes the DMZ host of the Livebox.
+func (c *Client) DeleteDMZ(ctx context.Context) error {
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  "deleteDMZ",
+		Parameters: map[string]any{
+			"id": DMZID,
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
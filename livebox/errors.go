@@ -0,0 +1,72 @@
+package livebox
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors that can be checked against errors returned by the client using errors.Is.
+var (
+	// ErrNotFound is returned when the requested object does not exist on the Livebox.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is returned when the Livebox rejected the credentials or the session.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrInvalidParameter is returned when the Livebox rejected one of the parameters of a request.
+	ErrInvalidParameter = errors.New("invalid parameter")
+	// ErrConflict is returned when a request would conflict with the existing configuration of the Livebox.
+	ErrConflict = errors.New("conflict")
+)
+
+// Known error codes returned by the Livebox API.
+const (
+	errorCodePermissionDenied = 13
+	errorCodeInvalidValue     = 22
+	errorCodeNotFound         = 196618
+	errorCodeInvalidParameter = 196619
+)
+
+// APIError is an error returned by the Livebox API.
+// It can be matched against the sentinel errors of this package using errors.Is, or
+// retrieved from an error returned by the client using errors.As.
+type APIError struct {
+	Code        int    `json:"error"`
+	Description string `json:"description"`
+	Info        string `json:"info"`
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.Info != "" {
+		return fmt.Sprintf("api error %d: %s (%s)", e.Code, e.Description, e.Info)
+	}
+
+	return fmt.Sprintf("api error %d: %s", e.Code, e.Description)
+}
+
+// Is reports whether the API error matches the given sentinel error.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.Code == errorCodePermissionDenied
+	case ErrNotFound:
+		return e.Code == errorCodeNotFound
+	case ErrInvalidParameter:
+		return e.Code == errorCodeInvalidValue || e.Code == errorCodeInvalidParameter
+	default:
+		return false
+	}
+}
+
+// apiErrors converts the error array of an API response into a single error.
+func apiErrors(errs []APIError) error {
+	if len(errs) == 1 {
+		return &errs[0]
+	}
+
+	joined := make([]error, 0, len(errs))
+	for i := range errs {
+		joined = append(joined, &errs[i])
+	}
+
+	return errors.Join(joined...)
+}
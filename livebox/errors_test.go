@@ -0,0 +1,104 @@
+package livebox
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAPIErrors(t *testing.T) {
+	sentinels := []error{ErrUnauthorized, ErrNotFound, ErrInvalidParameter, ErrConflict}
+
+	tests := map[string]struct {
+		payload   string
+		wantIs    []error
+		wantCodes []int
+	}{
+		"permission denied": {
+			payload:   `{"status":null,"errors":[{"error":13,"description":"Permission denied","info":""}]}`,
+			wantIs:    []error{ErrUnauthorized},
+			wantCodes: []int{13},
+		},
+		"object not found": {
+			payload:   `{"status":null,"errors":[{"error":196618,"description":"Object or parameter not found","info":"Firewall.Test"}]}`,
+			wantIs:    []error{ErrNotFound},
+			wantCodes: []int{196618},
+		},
+		"invalid value": {
+			payload:   `{"status":null,"errors":[{"error":22,"description":"Invalid value","info":"externalPort"}]}`,
+			wantIs:    []error{ErrInvalidParameter},
+			wantCodes: []int{22},
+		},
+		"invalid parameter": {
+			payload:   `{"status":null,"errors":[{"error":196619,"description":"Function argument is invalid","info":"origin"}]}`,
+			wantIs:    []error{ErrInvalidParameter},
+			wantCodes: []int{196619},
+		},
+		"unknown code": {
+			payload:   `{"status":null,"errors":[{"error":1,"description":"Unknown error","info":""}]}`,
+			wantCodes: []int{1},
+		},
+		"multiple errors": {
+			payload: `{"status":null,"errors":[` +
+				`{"error":22,"description":"Invalid value","info":"externalPort"},` +
+				`{"error":196618,"description":"Object or parameter not found","info":"Firewall.Test"}]}`,
+			wantIs:    []error{ErrInvalidParameter, ErrNotFound},
+			wantCodes: []int{22, 196618},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var resp apiResponse
+			if err := json.Unmarshal([]byte(tt.payload), &resp); err != nil {
+				t.Fatalf("unmarshal payload: %v", err)
+			}
+
+			err := apiErrors(resp.Errors)
+
+			for _, sentinel := range sentinels {
+				want := slices.Contains(tt.wantIs, sentinel)
+				if got := errors.Is(err, sentinel); got != want {
+					t.Errorf("errors.Is(%v, %v) = %t; want %t", err, sentinel, got, want)
+				}
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("errors.As(%v) found no *APIError", err)
+			}
+			if apiErr.Code != tt.wantCodes[0] {
+				t.Errorf("got first error code %d, want %d", apiErr.Code, tt.wantCodes[0])
+			}
+
+			var codes []int
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, e := range joined.Unwrap() {
+					codes = append(codes, e.(*APIError).Code)
+				}
+			} else {
+				codes = append(codes, apiErr.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) {
+				t.Errorf("got error codes %v, want %v", codes, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  APIError
+		want string
+	}{
+		{err: APIError{Code: 13, Description: "Permission denied"}, want: "api error 13: Permission denied"},
+		{err: APIError{Code: 22, Description: "Invalid value", Info: "externalPort"}, want: "api error 22: Invalid value (externalPort)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package livebox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FirewallLevel is a predefined security level of the firewall of the Livebox.
+type FirewallLevel string
+
+// List of supported firewall levels:
+const (
+	FirewallLevelLow    FirewallLevel = "low"
+	FirewallLevelMedium FirewallLevel = "medium"
+	FirewallLevelHigh   FirewallLevel = "high"
+	// FirewallLevelCustom only applies the custom rules defined on the Livebox.
+	FirewallLevelCustom FirewallLevel = "custom"
+)
+
+// apiString returns the representation of the level used by the Livebox API (e.g. "Medium").
+func (l FirewallLevel) apiString() string {
+	return capitalize(string(l))
+}
+
+// capitalize returns s with its first letter in upper case, which is how the Livebox API
+// spells most of its enumerated values.
+func capitalize(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func parseFirewallLevel(l string) FirewallLevel {
+	return FirewallLevel(strings.ToLower(l))
+}
+
+// FirewallSettings describes the global settings of the firewall of the Livebox.
+type FirewallSettings struct {
+	IPv4Level         FirewallLevel
+	IPv6Level         FirewallLevel
+	RespondToPingIPv4 bool
+	RespondToPingIPv6 bool
+}
+
+type getRespondToPingResp struct {
+	EnableIPv4 bool `json:"enableIPv4"`
+	EnableIPv6 bool `json:"enableIPv6"`
+}
+
+// GetFirewallSettings returns the global settings of the firewall of the Livebox.
+func (c *Client) GetFirewallSettings(ctx context.Context) (*FirewallSettings, error) {
+	ipv4Level, err := c.getFirewallLevel(ctx, "getFirewallLevel")
+	if err != nil {
+		return nil, fmt.Errorf("get IPv4 firewall level: %w", err)
+	}
+
+	ipv6Level, err := c.getFirewallLevel(ctx, "getFirewallIPv6Level")
+	if err != nil {
+		return nil, fmt.Errorf("get IPv6 firewall level: %w", err)
+	}
+
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  "getRespondToPing",
+		Parameters: map[string]any{
+			"sourceInterface": "data",
+		},
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	var pr getRespondToPingResp
+	if err = json.Unmarshal(data, &pr); err != nil {
+		return nil, fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	settings := FirewallSettings{
+		IPv4Level:         ipv4Level,
+		IPv6Level:         ipv6Level,
+		RespondToPingIPv4: pr.EnableIPv4,
+		RespondToPingIPv6: pr.EnableIPv6,
+	}
+
+	return &settings, nil
+}
+
+func (c *Client) getFirewallLevel(ctx context.Context, method string) (FirewallLevel, error) {
+	payload := &apiRequest{
+		Service:    "Firewall",
+		Method:     method,
+		Parameters: map[string]any{},
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return "", fmt.Errorf("do request: %w", err)
+	}
+
+	var level string
+	if err = json.Unmarshal(data, &level); err != nil {
+		return "", fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	return parseFirewallLevel(level), nil
+}
+
+// validate performs some basic validation on firewall settings.
+func (s FirewallSettings) validate() error {
+	for _, l := range []FirewallLevel{s.IPv4Level, s.IPv6Level} {
+		switch l {
+		case FirewallLevelLow, FirewallLevelMedium, FirewallLevelHigh, FirewallLevelCustom:
+		default:
+			return fmt.Errorf("invalid firewall level %q; must be one of: %q, %q, %q or %q",
+				l, FirewallLevelLow, FirewallLevelMedium, FirewallLevelHigh, FirewallLevelCustom)
+		}
+	}
+
+	return nil
+}
+
+// SetFirewallSettings updates the global settings of the firewall of the Livebox.
+func (c *Client) SetFirewallSettings(ctx context.Context, s FirewallSettings) error {
+	if err := s.validate(); err != nil {
+		return fmt.Errorf("validate configuration: %w", err)
+	}
+
+	if err := c.setFirewallLevel(ctx, "setFirewallLevel", s.IPv4Level); err != nil {
+		return fmt.Errorf("set IPv4 firewall level: %w", err)
+	}
+
+	if err := c.setFirewallLevel(ctx, "setFirewallIPv6Level", s.IPv6Level); err != nil {
+		return fmt.Errorf("set IPv6 firewall level: %w", err)
+	}
+
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  "setRespondToPing",
+		Parameters: map[string]any{
+			"sourceInterface": "data",
+			"service_enable": map[string]any{
+				"enableIPv4": s.RespondToPingIPv4,
+				"enableIPv6": s.RespondToPingIPv6,
+			},
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) setFirewallLevel(ctx context.Context, method string, level FirewallLevel) error {
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  method,
+		Parameters: map[string]any{
+			"level": level.apiString(),
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
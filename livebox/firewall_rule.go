@@ -0,0 +1,289 @@
+package livebox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FirewallRuleAction is what the firewall does with the traffic matching a custom rule.
+type FirewallRuleAction string
+
+// List of supported firewall rule actions:
+const (
+	FirewallRuleActionAccept FirewallRuleAction = "accept"
+	FirewallRuleActionDrop   FirewallRuleAction = "drop"
+)
+
+// FirewallRuleDirection is the direction of the traffic a custom firewall rule applies to.
+type FirewallRuleDirection string
+
+// List of supported firewall rule directions:
+const (
+	// FirewallRuleDirectionInbound applies to traffic coming from the Internet.
+	FirewallRuleDirectionInbound FirewallRuleDirection = "inbound"
+	// FirewallRuleDirectionOutbound applies to traffic going to the Internet.
+	FirewallRuleDirectionOutbound FirewallRuleDirection = "outbound"
+)
+
+// chain returns the chain of the firewall holding the custom rules of the given direction and IP version.
+func (d FirewallRuleDirection) chain(ipVersion int) string {
+	chain := "Custom"
+	if ipVersion == 6 {
+		chain += "_V6"
+	}
+	if d == FirewallRuleDirectionOutbound {
+		chain += "_Out"
+	}
+
+	return chain
+}
+
+// firewallRuleChains lists every chain holding custom rules, along with the direction and IP version of their rules.
+var firewallRuleChains = []struct {
+	direction FirewallRuleDirection
+	ipVersion int
+}{
+	{FirewallRuleDirectionInbound, 4},
+	{FirewallRuleDirectionOutbound, 4},
+	{FirewallRuleDirectionInbound, 6},
+	{FirewallRuleDirectionOutbound, 6},
+}
+
+// FirewallRule describes a custom rule of the firewall of the Livebox,
+// which is only applied when the firewall level is FirewallLevelCustom.
+// Source and destination ports of 0 match any port.
+type FirewallRule struct {
+	Name               string
+	Action             FirewallRuleAction
+	Direction          FirewallRuleDirection
+	Protocol           Protocol
+	IPVersion          int
+	SourcePrefix       string
+	SourcePort         int
+	SourcePortEnd      int
+	DestinationPrefix  string
+	DestinationPort    int
+	DestinationPortEnd int
+	Enabled            bool
+	// Status of the rule as reported by the Livebox (e.g. "Enabled", "Disabled" or "Error").
+	Status string
+}
+
+type getFirewallRuleResp struct {
+	ID                string `json:"Id"`
+	Target            string `json:"Target"`
+	Status            string `json:"Status"`
+	IPVersion         int    `json:"IPVersion"`
+	Protocol          string `json:"Protocol"`
+	DestinationPort   string `json:"DestinationPort"`
+	SourcePort        string `json:"SourcePort"`
+	DestinationPrefix string `json:"DestinationPrefix"`
+	SourcePrefix      string `json:"SourcePrefix"`
+	Enable            bool   `json:"Enable"`
+}
+
+// ListFirewallRules returns all the custom firewall rules currently configured, whatever their direction and IP version.
+func (c *Client) ListFirewallRules(ctx context.Context) ([]FirewallRule, error) {
+	var out []FirewallRule
+	for _, ch := range firewallRuleChains {
+		rules, err := c.listFirewallRules(ctx, ch.direction, ch.ipVersion)
+		if err != nil {
+			return nil, fmt.Errorf("list %s IPv%d rules: %w", ch.direction, ch.ipVersion, err)
+		}
+
+		out = append(out, rules...)
+	}
+
+	return out, nil
+}
+
+func (c *Client) listFirewallRules(ctx context.Context, direction FirewallRuleDirection, ipVersion int) ([]FirewallRule, error) {
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  "getCustomRules",
+		Parameters: map[string]any{
+			"chain": direction.chain(ipVersion),
+		},
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	var frr map[string]getFirewallRuleResp
+	if err = json.Unmarshal(data, &frr); err != nil {
+		return nil, fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	out := make([]FirewallRule, 0, len(frr))
+	for id, raw := range frr {
+		srcPort, srcPortEnd, err := parseOptionalPortRange(raw.SourcePort)
+		if err != nil {
+			return nil, fmt.Errorf("parse source port range: %w", err)
+		}
+
+		dstPort, dstPortEnd, err := parseOptionalPortRange(raw.DestinationPort)
+		if err != nil {
+			return nil, fmt.Errorf("parse destination port range: %w", err)
+		}
+
+		rule := FirewallRule{
+			Name:               id,
+			Action:             FirewallRuleAction(strings.ToLower(raw.Target)),
+			Direction:          direction,
+			Protocol:           parseProtocol(raw.Protocol),
+			IPVersion:          ipVersion,
+			SourcePrefix:       raw.SourcePrefix,
+			SourcePort:         srcPort,
+			SourcePortEnd:      srcPortEnd,
+			DestinationPrefix:  raw.DestinationPrefix,
+			DestinationPort:    dstPort,
+			DestinationPortEnd: dstPortEnd,
+			Enabled:            raw.Enable,
+			Status:             raw.Status,
+		}
+		out = append(out, rule)
+	}
+
+	return out, nil
+}
+
+// GetFirewallRule returns the custom firewall rule matching the given direction, IP version and name, if found.
+// If there is no such rule, the returned error matches ErrNotFound.
+// Like GetPortForwarding, this method lists all the rules of the chain and filters the result.
+func (c *Client) GetFirewallRule(ctx context.Context, direction FirewallRuleDirection, ipVersion int, name string) (*FirewallRule, error) {
+	rules, err := c.listFirewallRules(ctx, direction, ipVersion)
+	if err != nil {
+		return nil, fmt.Errorf("list firewall rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		if rule.Name == name {
+			return &rule, nil
+		}
+	}
+
+	return nil, fmt.Errorf("firewall rule %q: %w", name, ErrNotFound)
+}
+
+// FirewallRuleConfig configures a custom firewall rule.
+// Prefixes are optional addresses or CIDR prefixes of the given IP version, empty ones matching any address.
+// Protocol may be any valid protocol, but ports are only valid for TCP and UDP. Ports are optional;
+// a port of 0 matches any port, and a port range end of 0 means a single port is matched.
+type FirewallRuleConfig struct {
+	Name               string
+	Action             FirewallRuleAction
+	Direction          FirewallRuleDirection
+	Protocol           Protocol
+	IPVersion          int
+	SourcePrefix       string
+	SourcePort         int
+	SourcePortEnd      int
+	DestinationPrefix  string
+	DestinationPort    int
+	DestinationPortEnd int
+	Enabled            bool
+}
+
+// validate performs some basic validation on a firewall rule configuration.
+func (c FirewallRuleConfig) validate() error {
+	if c.Name == "" {
+		return errors.New("empty name")
+	}
+
+	if c.Action != FirewallRuleActionAccept && c.Action != FirewallRuleActionDrop {
+		return fmt.Errorf("invalid action; must be one of: %q or %q", FirewallRuleActionAccept, FirewallRuleActionDrop)
+	}
+
+	if c.Direction != FirewallRuleDirectionInbound && c.Direction != FirewallRuleDirectionOutbound {
+		return fmt.Errorf("invalid direction; must be one of: %q or %q", FirewallRuleDirectionInbound, FirewallRuleDirectionOutbound)
+	}
+
+	if c.IPVersion != 4 && c.IPVersion != 6 {
+		return errors.New("invalid IP version; must be 4 or 6")
+	}
+
+	if c.Protocol.intString() == "" {
+		return errors.New("invalid protocol; must be a protocol name or an IP protocol number between 0 and 255")
+	}
+
+	for _, p := range []struct {
+		name       string
+		prefix     string
+		start, end int
+	}{
+		{"source", c.SourcePrefix, c.SourcePort, c.SourcePortEnd},
+		{"destination", c.DestinationPrefix, c.DestinationPort, c.DestinationPortEnd},
+	} {
+		if p.prefix != "" && !IsValidPrefix(p.prefix, c.IPVersion) {
+			return fmt.Errorf("invalid %s prefix; must be a valid IPv%d address or CIDR prefix", p.name, c.IPVersion)
+		}
+
+		if p.start < 0 || p.start > 65535 {
+			return fmt.Errorf("invalid %s port; must be 0 (any) or between 1 and 65535", p.name)
+		}
+
+		if p.end != 0 && (p.start == 0 || p.end < p.start || p.end > 65535) {
+			return fmt.Errorf("invalid %s port range end; must be between the %s port and 65535", p.name, p.name)
+		}
+
+		if p.start != 0 && !c.Protocol.HasPorts() {
+			return fmt.Errorf("invalid %s port; ports are only supported by %q, %q and %q", p.name, "tcp", "udp", "tcp/udp")
+		}
+	}
+
+	return nil
+}
+
+// UpsertFirewallRule upserts the given custom firewall rule.
+func (c *Client) UpsertFirewallRule(ctx context.Context, cfg FirewallRuleConfig) error {
+	if err := cfg.validate(); err != nil {
+		return fmt.Errorf("validate configuration: %w", err)
+	}
+
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  "setCustomRule",
+		Parameters: map[string]any{
+			"id":                cfg.Name,
+			"chain":             cfg.Direction.chain(cfg.IPVersion),
+			"action":            capitalize(string(cfg.Action)),
+			"protocol":          cfg.Protocol.intString(),
+			"ipversion":         cfg.IPVersion,
+			"sourcePrefix":      cfg.SourcePrefix,
+			"sourcePort":        formatOptionalPortRange(cfg.SourcePort, cfg.SourcePortEnd),
+			"destinationPrefix": cfg.DestinationPrefix,
+			"destinationPort":   formatOptionalPortRange(cfg.DestinationPort, cfg.DestinationPortEnd),
+			"enable":            cfg.Enabled,
+			"persistent":        true,
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFirewallRule deletes the custom firewall rule matching the given direction, IP version and name.
+func (c *Client) DeleteFirewallRule(ctx context.Context, direction FirewallRuleDirection, ipVersion int, name string) error {
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  "deleteCustomRule",
+		Parameters: map[string]any{
+			"id":    name,
+			"chain": direction.chain(ipVersion),
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package livebox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// LANSettings describes the IPv4 addressing of the LAN of the Livebox, and the DHCP server serving it.
+type LANSettings struct {
+	// IPAddress is the IPv4 address of the Livebox on the LAN.
+	IPAddress string
+	Netmask   string
+	// DHCPEnabled reports whether the Livebox leases addresses of the DHCP pool to the devices of the LAN.
+	DHCPEnabled bool
+	// DHCPMinAddress and DHCPMaxAddress are the first and last addresses of the DHCP pool (inclusive).
+	DHCPMinAddress string
+	DHCPMaxAddress string
+	// DHCPLeaseTime is the duration of the leases in seconds.
+	DHCPLeaseTime int
+}
+
+type getLANIPResp struct {
+	Address        string `json:"Address"`
+	Netmask        string `json:"Netmask"`
+	DHCPEnable     bool   `json:"DHCPEnable"`
+	DHCPMinAddress string `json:"DHCPMinAddress"`
+	DHCPMaxAddress string `json:"DHCPMaxAddress"`
+}
+
+// GetLANSettings returns the IPv4 addressing of the LAN and the settings of its DHCP server.
+func (c *Client) GetLANSettings(ctx context.Context) (*LANSettings, error) {
+	lr, err := c.getLANIP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := c.GetDHCPPool(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get DHCP pool: %w", err)
+	}
+
+	settings := LANSettings{
+		IPAddress:      lr.Address,
+		Netmask:        lr.Netmask,
+		DHCPEnabled:    lr.DHCPEnable,
+		DHCPMinAddress: lr.DHCPMinAddress,
+		DHCPMaxAddress: lr.DHCPMaxAddress,
+		DHCPLeaseTime:  pool.LeaseTime,
+	}
+
+	return &settings, nil
+}
+
+func (c *Client) getLANIP(ctx context.Context) (*getLANIPResp, error) {
+	payload := &apiRequest{
+		Service:    "NMC",
+		Method:     "getLANIP",
+		Parameters: map[string]any{},
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	var lr getLANIPResp
+	if err = json.Unmarshal(data, &lr); err != nil {
+		return nil, fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	return &lr, nil
+}
+
+// Prefix returns the IPv4 prefix of the LAN, made of its IP address and netmask.
+func (s LANSettings) Prefix() (netip.Prefix, error) {
+	addr, err := netip.ParseAddr(s.IPAddress)
+	if err != nil || !addr.Is4() {
+		return netip.Prefix{}, errors.New("invalid IP address; must be a valid IPv4 address")
+	}
+
+	mask, err := netip.ParseAddr(s.Netmask)
+	if err != nil || !mask.Is4() {
+		return netip.Prefix{}, errors.New("invalid netmask; must be a valid IPv4 netmask")
+	}
+
+	// Size returns 0, 0 if the mask is not made of leading ones followed by zeros.
+	ones, bits := net.IPMask(mask.AsSlice()).Size()
+	if bits == 0 {
+		return netip.Prefix{}, errors.New("invalid netmask; must be a valid IPv4 netmask")
+	}
+
+	return addr.Prefix(ones)
+}
+
+// validate performs some basic validation on LAN settings.
+func (s LANSettings) validate() error {
+	prefix, err := s.Prefix()
+	if err != nil {
+		return err
+	}
+
+	minAddr, err := netip.ParseAddr(s.DHCPMinAddress)
+	if err != nil || !prefix.Contains(minAddr) {
+		return errors.New("invalid DHCP min address; must be an IPv4 address of the LAN")
+	}
+
+	maxAddr, err := netip.ParseAddr(s.DHCPMaxAddress)
+	if err != nil || !prefix.Contains(maxAddr) {
+		return errors.New("invalid DHCP max address; must be an IPv4 address of the LAN")
+	}
+
+	if minAddr.Compare(maxAddr) > 0 {
+		return errors.New("invalid DHCP pool; the min address must not be greater than the max address")
+	}
+
+	if addr, _ := netip.ParseAddr(s.IPAddress); addr.Compare(minAddr) >= 0 && addr.Compare(maxAddr) <= 0 {
+		return errors.New("invalid DHCP pool; it must not contain the IP address of the Livebox")
+	}
+
+	if s.DHCPLeaseTime <= 0 {
+		return errors.New("invalid DHCP lease time; must be positive")
+	}
+
+	return nil
+}
+
+// SetLANSettings updates the IPv4 addressing of the LAN and the settings of its DHCP server.
+// The IP address is changed last since the Livebox stops answering on its previous address right after.
+// If the client reaches the Livebox through its previous LAN address, it then uses the new one.
+// Changing the IP address of the Livebox may still break the connection of the client if it goes
+// through the LAN and the host running it is not renumbered.
+func (c *Client) SetLANSettings(ctx context.Context, s LANSettings) error {
+	if err := s.validate(); err != nil {
+		return fmt.Errorf("validate configuration: %w", err)
+	}
+
+	current, err := c.getLANIP(ctx)
+	if err != nil {
+		return fmt.Errorf("get current LAN settings: %w", err)
+	}
+
+	payload := &apiRequest{
+		Service: dhcpPoolService,
+		Method:  "setLeaseTime",
+		Parameters: map[string]any{
+			"leasetime": s.DHCPLeaseTime,
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("set DHCP lease time: %w", err)
+	}
+
+	payload = &apiRequest{
+		Service: "NMC",
+		Method:  "setLANIP",
+		Parameters: map[string]any{
+			"Address":        s.IPAddress,
+			"Netmask":        s.Netmask,
+			"DHCPEnable":     s.DHCPEnabled,
+			"DHCPMinAddress": s.DHCPMinAddress,
+			"DHCPMaxAddress": s.DHCPMaxAddress,
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	if current.Address != s.IPAddress {
+		c.moveHost(current.Address, s.IPAddress)
+	}
+
+	return nil
+}
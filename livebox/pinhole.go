@@ -0,0 +1,224 @@
+package livebox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Pinhole describes the configuration of an IPv6 pinhole, which allows inbound IPv6 traffic
+// to reach a host of the LAN through the firewall of the Livebox.
+// Its ID on the Livebox is made of its origin and its name (e.g. "webui_https").
+type Pinhole struct {
+	ID                 string
+	Name               string
+	Origin             string
+	Protocol           Protocol
+	DestinationPort    int
+	DestinationPortEnd int
+	Destination        string
+	Source             string
+	Enabled            bool
+	// Status of the pinhole as reported by the Livebox (e.g. "Enabled", "Disabled" or "Error").
+	Status string
+}
+
+type getPinholeResp struct {
+	ID                   string `json:"Id"`
+	Origin               string `json:"Origin"`
+	Description          string `json:"Description"`
+	Status               string `json:"Status"`
+	SourceInterface      string `json:"SourceInterface"`
+	Protocol             string `json:"Protocol"`
+	IPVersion            int    `json:"IPVersion"`
+	SourcePort           string `json:"SourcePort"`
+	DestinationPort      string `json:"DestinationPort"`
+	SourcePrefix         string `json:"SourcePrefix"`
+	DestinationIPAddress string `json:"DestinationIPAddress"`
+	Enable               bool   `json:"Enable"`
+}
+
+// ListPinholes returns all the IPv6 pinholes currently configured for the given origin.
+// If origin is empty, the pinholes of every origin are returned.
+func (c *Client) ListPinholes(ctx context.Context, origin string) ([]Pinhole, error) {
+	payload := &apiRequest{
+		Service:    "Firewall",
+		Method:     "getPinhole",
+		Parameters: map[string]any{},
+	}
+	if origin != "" {
+		payload.Parameters["origin"] = origin
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	var phr map[string]getPinholeResp
+	if err = json.Unmarshal(data, &phr); err != nil {
+		return nil, fmt.Errorf("unmarshal data: %w", err)
+	}
+
+	out := make([]Pinhole, 0, len(phr))
+	for id, raw := range phr {
+		port, portEnd, err := parseOptionalPortRange(raw.DestinationPort)
+		if err != nil {
+			return nil, fmt.Errorf("parse destination port range: %w", err)
+		}
+
+		ph := Pinhole{
+			ID:                 id,
+			Name:               strings.TrimPrefix(id, raw.Origin+"_"),
+			Origin:             raw.Origin,
+			Protocol:           parseProtocol(raw.Protocol),
+			DestinationPort:    port,
+			DestinationPortEnd: portEnd,
+			Destination:        raw.DestinationIPAddress,
+			Source:             raw.SourcePrefix,
+			Enabled:            raw.Enable,
+			Status:             raw.Status,
+		}
+		out = append(out, ph)
+	}
+
+	return out, nil
+}
+
+// GetPinhole returns the IPv6 pinhole matching the given origin and name, if found.
+// If origin is empty, OriginWebUI is used. If there is no such pinhole, the returned error matches ErrNotFound.
+// Like GetPortForwarding, this method lists all the pinholes and filters the result.
+func (c *Client) GetPinhole(ctx context.Context, origin, name string) (*Pinhole, error) {
+	if origin == "" {
+		origin = OriginWebUI
+	}
+
+	phs, err := c.ListPinholes(ctx, origin)
+	if err != nil {
+		return nil, fmt.Errorf("list pinholes: %w", err)
+	}
+
+	for _, ph := range phs {
+		if ph.Name == name {
+			return &ph, nil
+		}
+	}
+
+	return nil, fmt.Errorf("pinhole %q: %w", PinholeID(origin, name), ErrNotFound)
+}
+
+// PinholeID returns the ID of the IPv6 pinhole with the given origin and name on the Livebox.
+func PinholeID(origin, name string) string {
+	return origin + "_" + name
+}
+
+// PinholeConfig configures an IPv6 pinhole.
+// If Origin is empty, OriginWebUI is used. Protocol may be any valid protocol, but ports are only valid for TCP and UDP.
+// DestinationPort is optional, 0 matching any port. DestinationPortEnd is the last port of an inclusive range starting
+// at DestinationPort; if zero, a single port is opened. Source is an optional IPv6 address or prefix.
+type PinholeConfig struct {
+	Name               string
+	Origin             string
+	Protocol           Protocol
+	DestinationPort    int
+	DestinationPortEnd int
+	Destination        string
+	Source             string
+	Enabled            bool
+}
+
+// validate performs some basic validation on a pinhole configuration.
+func (c PinholeConfig) validate() error {
+	if c.Name == "" {
+		return errors.New("empty name")
+	}
+
+	if c.Protocol.intString() == "" {
+		return errors.New("invalid protocol; must be a protocol name or an IP protocol number between 0 and 255")
+	}
+
+	if c.DestinationPort < 0 || c.DestinationPort > 65535 {
+		return errors.New("invalid destination port; must be 0 (any) or between 1 and 65535")
+	}
+
+	if c.DestinationPortEnd != 0 && (c.DestinationPort == 0 || c.DestinationPortEnd < c.DestinationPort || c.DestinationPortEnd > 65535) {
+		return errors.New("invalid destination port range end; must be between the destination port and 65535")
+	}
+
+	if c.DestinationPort != 0 && !c.Protocol.HasPorts() {
+		return fmt.Errorf("invalid destination port; ports are only supported by %q, %q and %q", "tcp", "udp", "tcp/udp")
+	}
+
+	if dest := net.ParseIP(c.Destination); dest == nil || dest.To4() != nil {
+		return errors.New("invalid destination; must be a valid IPv6 address")
+	}
+
+	if c.Source != "" && !IsValidPrefix(c.Source, 6) {
+		return errors.New("invalid source; must be a valid IPv6 address or prefix")
+	}
+
+	return nil
+}
+
+// UpsertPinhole upserts the given IPv6 pinhole.
+func (c *Client) UpsertPinhole(ctx context.Context, cfg PinholeConfig) error {
+	if err := cfg.validate(); err != nil {
+		return fmt.Errorf("validate configuration: %w", err)
+	}
+
+	origin := cfg.Origin
+	if origin == "" {
+		origin = OriginWebUI
+	}
+
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  "setPinhole",
+		Parameters: map[string]any{
+			"id":                   PinholeID(origin, cfg.Name),
+			"description":          cfg.Name,
+			"protocol":             cfg.Protocol.intString(),
+			"destinationPort":      formatOptionalPortRange(cfg.DestinationPort, cfg.DestinationPortEnd),
+			"sourcePort":           "",
+			"destinationIPAddress": cfg.Destination,
+			"sourcePrefix":         cfg.Source,
+			"ipversion":            6,
+			"persistent":           true,
+			"enable":               cfg.Enabled,
+			"sourceInterface":      "data",
+			"origin":               origin,
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePinhole deletes the IPv6 pinhole matching the given origin and name.
+// If origin is empty, OriginWebUI is used.
+func (c *Client) DeletePinhole(ctx context.Context, origin, name string) error {
+	if origin == "" {
+		origin = OriginWebUI
+	}
+
+	payload := &apiRequest{
+		Service: "Firewall",
+		Method:  "deletePinhole",
+		Parameters: map[string]any{
+			"id":     PinholeID(origin, name),
+			"origin": origin,
+		},
+	}
+
+	if _, err := c.doReq(ctx, payload); err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	return nil
+}
@@ -1,24 +1,48 @@
 package livebox
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 )
 
+// OriginWebUI is the origin of the port forwarding rules created through the web interface of the Livebox.
+// It is the default origin used by the client when none is specified.
+const OriginWebUI = "webui"
+
 // PortForwarding describes the configuration of a port forwarding rule.
+// Its ID on the Livebox is made of its origin and its name (e.g. "webui_wireguard").
+// Ports are expressed as inclusive ranges, the end of a range being equal to its start when forwarding a single port.
 type PortForwarding struct {
-	Name         string
-	Protocol     Protocol
-	ExternalPort int
-	InternalPort int
-	PortRange    int
-	Destination  string
-	Source       string
-	Enabled      bool
+	ID              string
+	Name            string
+	Origin          string
+	Protocol        Protocol
+	ExternalPort    int
+	ExternalPortEnd int
+	InternalPort    int
+	InternalPortEnd int
+	Destination     string
+	Source          string
+	Enabled         bool
+
+	// HairpinNAT allows hosts of the LAN to reach the forwarded port through the public IP address of the Livebox.
+	HairpinNAT bool
+	// SymmetricSNAT rewrites the source address of hairpinned traffic so replies go back through the Livebox.
+	SymmetricSNAT bool
+	// UPnPV1Compat is set when the rule is compatible with UPnP IGD v1 clients.
+	UPnPV1Compat bool
+	// LeaseDuration is the duration of the rule in seconds, 0 meaning it never expires.
+	LeaseDuration int
+	// Status of the rule as reported by the Livebox (e.g. "Enabled", "Disabled" or "Error").
+	Status                string
+	DestinationMACAddress string
+	SourceInterface       string
 }
 
 type getPortForwardingResp struct {
@@ -40,17 +64,19 @@ type getPortForwardingResp struct {
 	Enable                bool   `json:"Enable"`
 }
 
-// ListPortForwardings returns all the port forwarding rules currently configured.
-func (c *Client) ListPortForwardings() ([]PortForwarding, error) {
+// ListPortForwardings returns all the port forwarding rules currently configured for the given origin
+// (e.g. "webui" or "upnp"). If origin is empty, the rules of every origin are returned.
+func (c *Client) ListPortForwardings(ctx context.Context, origin string) ([]PortForwarding, error) {
 	payload := &apiRequest{
-		Service: "Firewall",
-		Method:  "getPortForwarding",
-		Parameters: map[string]any{
-			"origin": "webui",
-		},
+		Service:    "Firewall",
+		Method:     "getPortForwarding",
+		Parameters: map[string]any{},
+	}
+	if origin != "" {
+		payload.Parameters["origin"] = origin
 	}
 
-	data, err := c.doReq(payload)
+	data, err := c.doReq(ctx, payload)
 	if err != nil {
 		return nil, fmt.Errorf("do request: %w", err)
 	}
@@ -62,25 +88,9 @@ func (c *Client) ListPortForwardings() ([]PortForwarding, error) {
 
 	out := make([]PortForwarding, 0, len(pfr))
 	for id, raw := range pfr {
-		externalPort, portRange, err := parsePortRange(raw.ExternalPort)
-		if err != nil {
-			return nil, fmt.Errorf("parse port range: %w", err)
-		}
-
-		internalPort, err := strconv.Atoi(raw.InternalPort)
+		pf, err := raw.portForwarding(id)
 		if err != nil {
-			return nil, fmt.Errorf("parse internal port: %w", err)
-		}
-
-		pf := PortForwarding{
-			Name:         strings.TrimPrefix(id, "webui_"),
-			Protocol:     parseProtocol(raw.Protocol),
-			ExternalPort: externalPort,
-			InternalPort: internalPort,
-			PortRange:    portRange,
-			Destination:  raw.DestinationIPAddress,
-			Source:       raw.SourcePrefix,
-			Enabled:      raw.Enable,
+			return nil, err
 		}
 		out = append(out, pf)
 	}
@@ -88,11 +98,71 @@ func (c *Client) ListPortForwardings() ([]PortForwarding, error) {
 	return out, nil
 }
 
-// GetPortForwarding returns the port forwarding rule configured matching the given name, if found.
+// portForwarding returns the port forwarding rule with the given ID described by the response.
+func (raw getPortForwardingResp) portForwarding(id string) (PortForwarding, error) {
+	externalPort, externalPortEnd, err := parsePortRange(raw.ExternalPort)
+	if err != nil {
+		return PortForwarding{}, fmt.Errorf("parse external port range of %q: %w", id, err)
+	}
+
+	internalPort, internalPortEnd, err := parsePortRange(raw.InternalPort)
+	if err != nil {
+		return PortForwarding{}, fmt.Errorf("parse internal port range of %q: %w", id, err)
+	}
+
+	pf := PortForwarding{
+		ID:              id,
+		Name:            strings.TrimPrefix(id, raw.Origin+"_"),
+		Origin:          raw.Origin,
+		Protocol:        parseProtocol(raw.Protocol),
+		ExternalPort:    externalPort,
+		ExternalPortEnd: externalPortEnd,
+		InternalPort:    internalPort,
+		InternalPortEnd: internalPortEnd,
+		Destination:     raw.DestinationIPAddress,
+		Source:          raw.SourcePrefix,
+		Enabled:         raw.Enable,
+
+		HairpinNAT:            raw.HairpinNAT,
+		SymmetricSNAT:         raw.SymmetricSNAT,
+		UPnPV1Compat:          raw.UPnPV1Compat,
+		LeaseDuration:         raw.LeaseDuration,
+		Status:                raw.Status,
+		DestinationMACAddress: raw.DestinationMACAddress,
+		SourceInterface:       raw.SourceInterface,
+	}
+
+	return pf, nil
+}
+
+// Config returns the configuration that writes the rule as it is, e.g. to restore it after it was deleted.
+func (pf PortForwarding) Config() PortForwardingConfig {
+	return PortForwardingConfig{
+		Name:            pf.Name,
+		Origin:          pf.Origin,
+		ExternalPort:    pf.ExternalPort,
+		ExternalPortEnd: pf.ExternalPortEnd,
+		InternalPort:    pf.InternalPort,
+		InternalPortEnd: pf.InternalPortEnd,
+		Protocol:        pf.Protocol,
+		Destination:     pf.Destination,
+		Source:          pf.Source,
+		Enabled:         pf.Enabled,
+		HairpinNAT:      pf.HairpinNAT,
+		SymmetricSNAT:   pf.SymmetricSNAT,
+	}
+}
+
+// GetPortForwarding returns the port forwarding rule configured matching the given origin and name, if found.
+// If origin is empty, OriginWebUI is used. If there is no such rule, the returned error matches ErrNotFound.
 // This method is just implemented for convenience, as the Livebox API does not seem to expose an endpoint
 // to retrieve a single port forwarding rule, so it lists them all and filters the result.
-func (c *Client) GetPortForwarding(name string) (*PortForwarding, error) {
-	pfs, err := c.ListPortForwardings()
+func (c *Client) GetPortForwarding(ctx context.Context, origin, name string) (*PortForwarding, error) {
+	if origin == "" {
+		origin = OriginWebUI
+	}
+
+	pfs, err := c.ListPortForwardings(ctx, origin)
 	if err != nil {
 		return nil, fmt.Errorf("list port forwardings: %w", err)
 	}
@@ -103,19 +173,32 @@ func (c *Client) GetPortForwarding(name string) (*PortForwarding, error) {
 		}
 	}
 
-	return nil, errors.New("port forward not found")
+	return nil, fmt.Errorf("port forwarding %q: %w", PortForwardingID(origin, name), ErrNotFound)
+}
+
+// PortForwardingID returns the ID of the port forwarding rule with the given origin and name on the Livebox.
+func PortForwardingID(origin, name string) string {
+	return origin + "_" + name
 }
 
 // PortForwardingConfig configures a port forwarding rule.
+// If Origin is empty, OriginWebUI is used. ExternalPortEnd and InternalPortEnd are the last ports of inclusive ranges
+// starting at ExternalPort and InternalPort; if zero, a single port is forwarded. When both sides are ranges, they must
+// have the same length. Note that internal port ranges are not supported by every firmware.
 type PortForwardingConfig struct {
-	Name         string
-	ExternalPort int
-	InternalPort int
-	PortRange    int
-	Protocol     Protocol
-	Destination  string
-	Source       string
-	Enabled      bool
+	Name            string
+	Origin          string
+	ExternalPort    int
+	ExternalPortEnd int
+	InternalPort    int
+	InternalPortEnd int
+	Protocol        Protocol
+	Destination     string
+	Source          string
+	Enabled         bool
+
+	HairpinNAT    bool
+	SymmetricSNAT bool
 }
 
 // validate performs some basic validation on a port forward rule configuration.
@@ -129,14 +212,22 @@ func (c PortForwardingConfig) validate() error {
 	}
 
 	if c.InternalPort < 1 || c.InternalPort > 65535 {
-		return errors.New("invalid external port; must be between 1 and 65535")
+		return errors.New("invalid internal port; must be between 1 and 65535")
+	}
+
+	if c.ExternalPortEnd != 0 && (c.ExternalPortEnd < c.ExternalPort || c.ExternalPortEnd > 65535) {
+		return errors.New("invalid external port range end; must be between the external port and 65535")
 	}
 
-	if c.PortRange < 0 || c.PortRange > 65535 {
-		return errors.New("invalid port range; must be between 1 and 65535")
+	if c.InternalPortEnd != 0 && (c.InternalPortEnd < c.InternalPort || c.InternalPortEnd > 65535) {
+		return errors.New("invalid internal port range end; must be between the internal port and 65535")
 	}
 
-	if c.Protocol != ProtocolTCP && c.Protocol != ProtocolUDP && c.Protocol != ProtocolTCPUDP {
+	if internalLen := c.internalPortEnd() - c.InternalPort; internalLen != 0 && internalLen != c.externalPortEnd()-c.ExternalPort {
+		return errors.New("invalid internal port range; must have the same length as the external port range")
+	}
+
+	if !c.Protocol.HasPorts() {
 		return fmt.Errorf("invalid protocol; must be one of: %q, %q or %q", "tcp", "udp", "tcp/udp")
 	}
 
@@ -145,11 +236,11 @@ func (c PortForwardingConfig) validate() error {
 	}
 
 	// c.Source is optional, so if empty we don't need to validate it (it's fine)
-	// However, it can also be a list of IP addresses separated by commas, so we need to validate that.
+	// However, it can also be a list of IP addresses or IPv4 CIDR prefixes separated by commas, so we need to validate that.
 	if c.Source != "" {
 		for _, src := range strings.Split(c.Source, ",") {
-			if src = strings.TrimSpace(src); net.ParseIP(src) == nil {
-				return errors.New("invalid source; must be a valid IP address or a list of IP addresses separated by commas")
+			if !IsValidSourcePrefix(strings.TrimSpace(src)) {
+				return errors.New("invalid source; must be a valid IP address, IPv4 CIDR prefix or a list of those separated by commas")
 			}
 		}
 	}
@@ -158,82 +249,287 @@ func (c PortForwardingConfig) validate() error {
 }
 
 // UpsertPortForwarding upserts the given port forwarding rule.
-func (c *Client) UpsertPortForwarding(cfg PortForwardingConfig) error {
+// If the rule is enabled and its external ports overlap with another enabled rule using the same protocol,
+// nothing is written and a *PortForwardingConflictError is returned.
+func (c *Client) UpsertPortForwarding(ctx context.Context, cfg PortForwardingConfig) error {
 	if err := cfg.validate(); err != nil {
 		return fmt.Errorf("validate configuration: %w", err)
 	}
 
+	origin := cfg.Origin
+	if origin == "" {
+		origin = OriginWebUI
+	}
+
+	if err := c.checkPortForwardingConflicts(ctx, origin, cfg); err != nil {
+		return err
+	}
+
 	payload := &apiRequest{
 		Service: "Firewall",
 		Method:  "setPortForwarding",
 		Parameters: map[string]any{
-			"id":                   "webui_" + cfg.Name,
+			"id":                   PortForwardingID(origin, cfg.Name),
 			"description":          cfg.Name,
 			"protocol":             cfg.Protocol.intString(),
-			"internalPort":         cfg.InternalPort,
-			"externalPort":         formatPortRange(cfg.ExternalPort, cfg.PortRange),
+			"internalPort":         formatPortRange(cfg.InternalPort, cfg.internalPortEnd()),
+			"externalPort":         formatPortRange(cfg.ExternalPort, cfg.externalPortEnd()),
 			"destinationIPAddress": cfg.Destination,
 			"sourcePrefix":         cfg.Source,
 			"persistent":           true,
 			"enable":               cfg.Enabled,
+			"hairpinNAT":           cfg.HairpinNAT,
+			"symmetricSNAT":        cfg.SymmetricSNAT,
 			"sourceInterface":      "data",
-			"origin":               "webui",
+			"origin":               origin,
 		},
 	}
 
-	if _, err := c.doReq(payload); err != nil {
+	if _, err := c.doReq(ctx, payload); err != nil {
 		return fmt.Errorf("do request: %w", err)
 	}
 
 	return nil
 }
 
-// DeletePortForwarding deletes the port forwarding rule matching the given name.
-func (c *Client) DeletePortForwarding(name string) error {
+// CheckPortForwardings validates the given rules and checks that writing all of them would not make enabled rules
+// overlap, neither with each other nor with the rules of any origin already configured on the Livebox. Existing rules
+// whose ID is in replacedIDs are ignored, since they are about to be deleted or overwritten. It is meant to be called
+// before replacing several rules at once, so that nothing is deleted if one of them cannot be written.
+// A conflict is reported as a *PortForwardingConflictError.
+func (c *Client) CheckPortForwardings(ctx context.Context, cfgs []PortForwardingConfig, replacedIDs []string) error {
+	for _, cfg := range cfgs {
+		if err := cfg.validate(); err != nil {
+			return fmt.Errorf("validate configuration of %q: %w", cfg.Name, err)
+		}
+	}
+
+	others, err := c.listComparablePortForwardings(ctx)
+	if err != nil {
+		return err
+	}
+
+	others = slices.DeleteFunc(others, func(pf PortForwarding) bool {
+		return slices.Contains(replacedIDs, pf.ID)
+	})
+
+	for _, cfg := range cfgs {
+		origin := cfg.Origin
+		if origin == "" {
+			origin = OriginWebUI
+		}
+
+		if err := cfg.checkConflicts(origin, others); err != nil {
+			return err
+		}
+
+		others = append(others, cfg.portForwarding(origin))
+	}
+
+	return nil
+}
+
+// checkPortForwardingConflicts returns a *PortForwardingConflictError if the given rule would overlap
+// with another enabled rule of any origin already configured on the Livebox.
+func (c *Client) checkPortForwardingConflicts(ctx context.Context, origin string, cfg PortForwardingConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	pfs, err := c.listComparablePortForwardings(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cfg.checkConflicts(origin, pfs)
+}
+
+// listComparablePortForwardings returns the port forwarding rules of every origin configured on the Livebox.
+// Rules of other origins (e.g. "upnp") may use port formats this package does not understand. Their ports
+// cannot be compared, so they are skipped rather than blocking every write.
+func (c *Client) listComparablePortForwardings(ctx context.Context) ([]PortForwarding, error) {
+	payload := &apiRequest{
+		Service:    "Firewall",
+		Method:     "getPortForwarding",
+		Parameters: map[string]any{},
+	}
+
+	data, err := c.doReq(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("list port forwardings: %w", err)
+	}
+
+	var pfr map[string]getPortForwardingResp
+	if err = json.Unmarshal(data, &pfr); err != nil {
+		return nil, fmt.Errorf("unmarshal port forwardings: %w", err)
+	}
+
+	out := make([]PortForwarding, 0, len(pfr))
+	for id, raw := range pfr {
+		if pf, err := raw.portForwarding(id); err == nil {
+			out = append(out, pf)
+		}
+	}
+
+	return out, nil
+}
+
+// checkConflicts returns a *PortForwardingConflictError if the rule, written with the given origin,
+// would overlap with one of the given enabled rules.
+func (c PortForwardingConfig) checkConflicts(origin string, pfs []PortForwarding) error {
+	if !c.Enabled {
+		return nil
+	}
+
+	id := PortForwardingID(origin, c.Name)
+	for _, pf := range pfs {
+		if pf.ID == id || !pf.Enabled || !pf.Protocol.overlaps(c.Protocol) {
+			continue
+		}
+
+		if pf.ExternalPort <= c.externalPortEnd() && c.ExternalPort <= pf.ExternalPortEnd {
+			return &PortForwardingConflictError{Name: c.Name, Conflicting: pf}
+		}
+	}
+
+	return nil
+}
+
+// portForwarding returns the rule the configuration describes once written with the given origin.
+func (c PortForwardingConfig) portForwarding(origin string) PortForwarding {
+	return PortForwarding{
+		ID:              PortForwardingID(origin, c.Name),
+		Name:            c.Name,
+		Origin:          origin,
+		Protocol:        c.Protocol,
+		ExternalPort:    c.ExternalPort,
+		ExternalPortEnd: c.externalPortEnd(),
+		InternalPort:    c.InternalPort,
+		InternalPortEnd: c.internalPortEnd(),
+		Destination:     c.Destination,
+		Source:          c.Source,
+		Enabled:         c.Enabled,
+		HairpinNAT:      c.HairpinNAT,
+		SymmetricSNAT:   c.SymmetricSNAT,
+	}
+}
+
+// PortForwardingConflictError is returned when a port forwarding rule would overlap with an existing one.
+// It matches ErrConflict when using errors.Is.
+type PortForwardingConflictError struct {
+	// Name of the rule that could not be written.
+	Name string
+	// Conflicting is the existing rule that overlaps with it.
+	Conflicting PortForwarding
+}
+
+// Error implements the error interface.
+func (e *PortForwardingConflictError) Error() string {
+	return fmt.Sprintf(
+		"rule %q overlaps with rule %q, which already forwards external ports %s (%s)",
+		e.Name, e.Conflicting.ID, formatPortRange(e.Conflicting.ExternalPort, e.Conflicting.ExternalPortEnd), e.Conflicting.Protocol,
+	)
+}
+
+// Is reports whether the error matches the given sentinel error.
+func (e *PortForwardingConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// DeletePortForwarding deletes the port forwarding rule matching the given origin and name.
+// If origin is empty, OriginWebUI is used.
+func (c *Client) DeletePortForwarding(ctx context.Context, origin, name string) error {
+	if origin == "" {
+		origin = OriginWebUI
+	}
+
 	payload := &apiRequest{
 		Service: "Firewall",
 		Method:  "deletePortForwarding",
 		Parameters: map[string]any{
-			"id":     "webui_" + name,
-			"origin": "webui",
+			"id":     PortForwardingID(origin, name),
+			"origin": origin,
 		},
 	}
 
-	if _, err := c.doReq(payload); err != nil {
+	if _, err := c.doReq(ctx, payload); err != nil {
 		return fmt.Errorf("do request: %w", err)
 	}
 
 	return nil
 }
 
+// externalPortEnd returns the last port of the external port range, which is the external port itself if the
+// configuration does not describe a range.
+func (c PortForwardingConfig) externalPortEnd() int {
+	if c.ExternalPortEnd == 0 {
+		return c.ExternalPort
+	}
+
+	return c.ExternalPortEnd
+}
+
+// internalPortEnd returns the last port of the internal port range, which is the internal port itself if the
+// configuration does not describe a range.
+func (c PortForwardingConfig) internalPortEnd() int {
+	if c.InternalPortEnd == 0 {
+		return c.InternalPort
+	}
+
+	return c.InternalPortEnd
+}
+
 // parsePortRange parses a port expressed as a string, with an optional range written
-// using the following syntax: "10000-10005".
-func parsePortRange(port string) (externalPort int, portRange int, err error) {
+// using the following syntax: "10000-10005". If there is no range, end is equal to start.
+func parsePortRange(port string) (start int, end int, err error) {
 	parts := strings.SplitN(port, "-", 2)
-	externalPortStart, err := strconv.Atoi(parts[0])
+	start, err = strconv.Atoi(strings.TrimSpace(parts[0]))
 	if err != nil {
-		return 0, 0, fmt.Errorf("parse external port: %w", err)
+		return 0, 0, fmt.Errorf("parse port: %w", err)
 	}
 
 	// We only have one part, there is no range specified.
 	if len(parts) == 1 {
-		return externalPortStart, 0, nil
+		return start, start, nil
 	}
 
-	externalPortEnd, err := strconv.Atoi(parts[1])
+	end, err = strconv.Atoi(strings.TrimSpace(parts[1]))
 	if err != nil {
-		return 0, 0, fmt.Errorf("parse external port range: %w", err)
+		return 0, 0, fmt.Errorf("parse port range end: %w", err)
+	}
+
+	return start, end, nil
+}
+
+// formatPortRange does the opposite of parsePortRange. It returns an inclusive range of ports as a string
+// using the following syntax: "10000-10005". If end is equal to start, then the port is simply converted to a string.
+func formatPortRange(start, end int) string {
+	if end == start {
+		return strconv.Itoa(start)
 	}
 
-	return externalPortStart, externalPortEnd - externalPortStart, nil
+	return fmt.Sprintf("%d-%d", start, end)
 }
 
-// formatPortRange does the opposite of parsePortRange. It returns a port and an optional range as a string
-// using the following syntax: "10000-10005". If the range is 0 or 1, then the port is simply converted to a string.
-func formatPortRange(port, portRange int) string {
-	if portRange == 0 || portRange == 1 {
-		return strconv.Itoa(port)
+// parseOptionalPortRange is like parsePortRange, except that an empty string matches any port
+// and is returned as a range from 0 to 0.
+func parseOptionalPortRange(port string) (start int, end int, err error) {
+	if strings.TrimSpace(port) == "" {
+		return 0, 0, nil
+	}
+
+	return parsePortRange(port)
+}
+
+// formatOptionalPortRange does the opposite of parseOptionalPortRange.
+// If end is 0, a single port is formatted.
+func formatOptionalPortRange(start, end int) string {
+	if start == 0 {
+		return ""
+	}
+	if end == 0 {
+		end = start
 	}
 
-	return fmt.Sprintf("%s-%s", strconv.Itoa(port), strconv.Itoa(port+portRange))
+	return formatPortRange(start, end)
 }
@@ -0,0 +1,375 @@
+package livebox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		in         string
+		start, end int
+		wantErr    bool
+	}{
+		{in: "443", start: 443, end: 443},
+		{in: "10000-10005", start: 10000, end: 10005},
+		{in: "8080-8080", start: 8080, end: 8080},
+		{in: " 20 - 21 ", start: 20, end: 21},
+		{in: "", wantErr: true},
+		{in: "http", wantErr: true},
+		{in: "1000-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		start, end, err := parsePortRange(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePortRange(%q): got error %v, want error: %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if start != tt.start || end != tt.end {
+			t.Errorf("parsePortRange(%q) = %d, %d; want %d, %d", tt.in, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestFormatPortRange(t *testing.T) {
+	tests := []struct {
+		start, end int
+		want       string
+	}{
+		{start: 443, end: 443, want: "443"},
+		{start: 10000, end: 10005, want: "10000-10005"},
+		{start: 1, end: 65535, want: "1-65535"},
+	}
+
+	for _, tt := range tests {
+		if got := formatPortRange(tt.start, tt.end); got != tt.want {
+			t.Errorf("formatPortRange(%d, %d) = %q; want %q", tt.start, tt.end, got, tt.want)
+		}
+
+		start, end, err := parsePortRange(formatPortRange(tt.start, tt.end))
+		if err != nil {
+			t.Errorf("parsePortRange(formatPortRange(%d, %d)): %v", tt.start, tt.end, err)
+			continue
+		}
+		if start != tt.start || end != tt.end {
+			t.Errorf("round trip of %d-%d got %d-%d", tt.start, tt.end, start, end)
+		}
+	}
+}
+
+func TestPortForwardingConfigValidatePorts(t *testing.T) {
+	valid := PortForwardingConfig{
+		Name:         "test",
+		ExternalPort: 443,
+		InternalPort: 8443,
+		Protocol:     ProtocolTCP,
+		Destination:  "192.168.1.10",
+	}
+
+	tests := map[string]struct {
+		update  func(c *PortForwardingConfig)
+		wantErr bool
+	}{
+		"single port": {
+			update: func(*PortForwardingConfig) {},
+		},
+		"external range": {
+			update: func(c *PortForwardingConfig) { c.ExternalPortEnd = 450 },
+		},
+		"external and internal ranges": {
+			update: func(c *PortForwardingConfig) { c.ExternalPortEnd = 450; c.InternalPortEnd = 8450 },
+		},
+		"end equal to start": {
+			update: func(c *PortForwardingConfig) { c.ExternalPortEnd = 443; c.InternalPortEnd = 8443 },
+		},
+		"external end lower than start": {
+			update:  func(c *PortForwardingConfig) { c.ExternalPortEnd = 442 },
+			wantErr: true,
+		},
+		"internal end lower than start": {
+			update:  func(c *PortForwardingConfig) { c.InternalPortEnd = 8442 },
+			wantErr: true,
+		},
+		"external port above 65535": {
+			update:  func(c *PortForwardingConfig) { c.ExternalPort = 65536 },
+			wantErr: true,
+		},
+		"external end above 65535": {
+			update:  func(c *PortForwardingConfig) { c.ExternalPortEnd = 65536 },
+			wantErr: true,
+		},
+		"internal end above 65535": {
+			update: func(c *PortForwardingConfig) {
+				c.ExternalPort = 65530
+				c.ExternalPortEnd = 65535
+				c.InternalPort = 65531
+				c.InternalPortEnd = 65536
+			},
+			wantErr: true,
+		},
+		"ranges of different lengths": {
+			update:  func(c *PortForwardingConfig) { c.ExternalPortEnd = 450; c.InternalPortEnd = 8445 },
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := valid
+			tt.update(&c)
+
+			if err := c.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newPortForwardingTestClient returns a client of a fake Livebox configured with the given port forwarding rules,
+// and the number of rules it has been asked to write.
+func newPortForwardingTestClient(t *testing.T, rules map[string]getPortForwardingResp) (*Client, *atomic.Int32) {
+	t.Helper()
+
+	var writes atomic.Int32
+	f := &fakeLivebox{}
+	f.handle = func(w http.ResponseWriter, _ string, req *apiRequest) {
+		switch req.Method {
+		case "getPortForwarding":
+			_ = json.NewEncoder(w).Encode(map[string]any{"status": rules})
+		case "setPortForwarding":
+			writes.Add(1)
+			writeStatus(w)
+		default:
+			t.Errorf("unexpected request %s.%s", req.Service, req.Method)
+		}
+	}
+
+	return newTestClient(t, f), &writes
+}
+
+func TestUpsertPortForwardingConflicts(t *testing.T) {
+	upnp := getPortForwardingResp{
+		Origin:               "upnp",
+		Protocol:             "6",
+		ExternalPort:         "8000-8010",
+		InternalPort:         "8000-8010",
+		DestinationIPAddress: "192.168.1.20",
+		Enable:               true,
+	}
+
+	disabled := upnp
+	disabled.Enable = false
+
+	unparsable := upnp
+	unparsable.ExternalPort = "8005,8006"
+
+	self := upnp
+	self.Origin = OriginWebUI
+
+	tests := map[string]struct {
+		rules        map[string]getPortForwardingResp
+		protocol     Protocol
+		enabled      bool
+		wantConflict bool
+	}{
+		"overlap with another origin": {
+			rules:        map[string]getPortForwardingResp{"upnp_game": upnp},
+			protocol:     ProtocolTCP,
+			enabled:      true,
+			wantConflict: true,
+		},
+		"overlap with tcp/udp": {
+			rules:        map[string]getPortForwardingResp{"upnp_game": upnp},
+			protocol:     ProtocolTCPUDP,
+			enabled:      true,
+			wantConflict: true,
+		},
+		"other protocol": {
+			rules:    map[string]getPortForwardingResp{"upnp_game": upnp},
+			protocol: ProtocolUDP,
+			enabled:  true,
+		},
+		"existing rule disabled": {
+			rules:    map[string]getPortForwardingResp{"upnp_game": disabled},
+			protocol: ProtocolTCP,
+			enabled:  true,
+		},
+		"written rule disabled": {
+			rules:    map[string]getPortForwardingResp{"upnp_game": upnp},
+			protocol: ProtocolTCP,
+		},
+		"update of the rule itself": {
+			rules:    map[string]getPortForwardingResp{"webui_test": self},
+			protocol: ProtocolTCP,
+			enabled:  true,
+		},
+		"unparsable existing rule": {
+			rules:    map[string]getPortForwardingResp{"upnp_game": unparsable},
+			protocol: ProtocolTCP,
+			enabled:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, writes := newPortForwardingTestClient(t, tt.rules)
+
+			err := c.UpsertPortForwarding(context.Background(), PortForwardingConfig{
+				Name:         "test",
+				ExternalPort: 8005,
+				InternalPort: 8005,
+				Protocol:     tt.protocol,
+				Destination:  "192.168.1.10",
+				Enabled:      tt.enabled,
+			})
+
+			if !tt.wantConflict {
+				if err != nil {
+					t.Fatalf("UpsertPortForwarding: %v", err)
+				}
+				if got := writes.Load(); got != 1 {
+					t.Errorf("got %d writes, want 1", got)
+				}
+				return
+			}
+
+			var conflict *PortForwardingConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("got error %v, want a *PortForwardingConflictError", err)
+			}
+			if !errors.Is(err, ErrConflict) {
+				t.Errorf("got error %v, want it to match ErrConflict", err)
+			}
+			if conflict.Name != "test" || conflict.Conflicting.ID != "upnp_game" {
+				t.Errorf("got conflict of %q with %q, want conflict of %q with %q", conflict.Name, conflict.Conflicting.ID, "test", "upnp_game")
+			}
+			if got := writes.Load(); got != 0 {
+				t.Errorf("got %d writes, want none", got)
+			}
+		})
+	}
+}
+
+func TestCheckPortForwardings(t *testing.T) {
+	existing := map[string]getPortForwardingResp{
+		"webui_lab-ssh": {
+			Origin:               OriginWebUI,
+			Protocol:             "6",
+			ExternalPort:         "22",
+			InternalPort:         "22",
+			DestinationIPAddress: "192.168.1.20",
+			Enable:               true,
+		},
+	}
+
+	ssh := PortForwardingConfig{
+		Name:         "lab-ssh2",
+		ExternalPort: 22,
+		InternalPort: 22,
+		Protocol:     ProtocolTCP,
+		Destination:  "192.168.1.10",
+		Enabled:      true,
+	}
+
+	https := ssh
+	https.Name = "lab-https"
+	https.ExternalPort = 443
+	https.InternalPort = 443
+
+	otherSSH := https
+	otherSSH.Name = "lab-z"
+	otherSSH.ExternalPort = 22
+
+	invalid := https
+	invalid.ExternalPortEnd = 442
+
+	tests := map[string]struct {
+		cfgs         []PortForwardingConfig
+		replacedIDs  []string
+		wantConflict string
+		wantErr      bool
+	}{
+		"no conflict": {
+			cfgs: []PortForwardingConfig{https},
+		},
+		"conflict with an existing rule": {
+			cfgs:         []PortForwardingConfig{https, ssh},
+			wantConflict: "lab-ssh2",
+		},
+		"existing rule replaced": {
+			cfgs:        []PortForwardingConfig{https, ssh},
+			replacedIDs: []string{"webui_lab-ssh"},
+		},
+		"conflict between declared rules": {
+			cfgs:         []PortForwardingConfig{ssh, otherSSH},
+			replacedIDs:  []string{"webui_lab-ssh"},
+			wantConflict: "lab-z",
+		},
+		"invalid rule": {
+			cfgs:    []PortForwardingConfig{invalid},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, writes := newPortForwardingTestClient(t, existing)
+
+			err := c.CheckPortForwardings(context.Background(), tt.cfgs, tt.replacedIDs)
+
+			var conflict *PortForwardingConflictError
+			switch {
+			case tt.wantConflict != "":
+				if !errors.As(err, &conflict) || conflict.Name != tt.wantConflict {
+					t.Errorf("got error %v, want a conflict of %q", err, tt.wantConflict)
+				}
+			case tt.wantErr:
+				if err == nil || errors.As(err, &conflict) {
+					t.Errorf("got error %v, want a validation error", err)
+				}
+			case err != nil:
+				t.Errorf("CheckPortForwardings: %v", err)
+			}
+
+			if got := writes.Load(); got != 0 {
+				t.Errorf("got %d writes, want none", got)
+			}
+		})
+	}
+}
+
+func TestOptionalPortRange(t *testing.T) {
+	tests := []struct {
+		in         string
+		start, end int
+		formatted  string
+	}{
+		{in: "", start: 0, end: 0, formatted: ""},
+		{in: " ", start: 0, end: 0, formatted: ""},
+		{in: "53", start: 53, end: 53, formatted: "53"},
+		{in: "6000-6010", start: 6000, end: 6010, formatted: "6000-6010"},
+	}
+
+	for _, tt := range tests {
+		start, end, err := parseOptionalPortRange(tt.in)
+		if err != nil {
+			t.Errorf("parseOptionalPortRange(%q): %v", tt.in, err)
+			continue
+		}
+		if start != tt.start || end != tt.end {
+			t.Errorf("parseOptionalPortRange(%q) = %d, %d; want %d, %d", tt.in, start, end, tt.start, tt.end)
+		}
+		if got := formatOptionalPortRange(start, end); got != tt.formatted {
+			t.Errorf("formatOptionalPortRange(%d, %d) = %q; want %q", start, end, got, tt.formatted)
+		}
+	}
+
+	if got := formatOptionalPortRange(53, 0); got != "53" {
+		t.Errorf("formatOptionalPortRange(53, 0) = %q; want %q", got, "53")
+	}
+}
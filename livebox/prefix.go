@@ -0,0 +1,30 @@
+package livebox
+
+import (
+	"net/netip"
+)
+
+// IsValidPrefix reports whether the given string is either an address or a CIDR prefix of the given IP version.
+func IsValidPrefix(s string, ipVersion int) bool {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return false
+		}
+
+		addr = prefix.Addr()
+	}
+
+	return ipVersion == 4 && addr.Is4() || ipVersion == 6 && addr.Is6() && !addr.Is4In6()
+}
+
+// IsValidSourcePrefix reports whether the given source is either an IP address or an IPv4 CIDR prefix
+// (e.g. "192.0.2.0/24"), which are the formats accepted by the Livebox for port forwarding and DMZ sources.
+func IsValidSourcePrefix(src string) bool {
+	if _, err := netip.ParseAddr(src); err == nil {
+		return true
+	}
+
+	return IsValidPrefix(src, 4)
+}
@@ -0,0 +1,48 @@
+package livebox
+
+import (
+	"testing"
+)
+
+func TestIsValidPrefix(t *testing.T) {
+	tests := []struct {
+		in        string
+		ipVersion int
+		want      bool
+	}{
+		{in: "192.0.2.1", ipVersion: 4, want: true},
+		{in: "192.0.2.0/24", ipVersion: 4, want: true},
+		{in: "2001:db8::1", ipVersion: 6, want: true},
+		{in: "2001:db8::/32", ipVersion: 6, want: true},
+		{in: "192.0.2.1", ipVersion: 6, want: false},
+		{in: "2001:db8::/32", ipVersion: 4, want: false},
+		{in: "::ffff:192.0.2.1", ipVersion: 6, want: false},
+		{in: "192.0.2.0/33", ipVersion: 4, want: false},
+		{in: "not an address", ipVersion: 4, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidPrefix(tt.in, tt.ipVersion); got != tt.want {
+			t.Errorf("IsValidPrefix(%q, %d) = %t; want %t", tt.in, tt.ipVersion, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidSourcePrefix(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{in: "192.0.2.1", want: true},
+		{in: "192.0.2.0/24", want: true},
+		{in: "2001:db8::1", want: true},
+		{in: "2001:db8::/32", want: false},
+		{in: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidSourcePrefix(tt.in); got != tt.want {
+			t.Errorf("IsValidSourcePrefix(%q) = %t; want %t", tt.in, got, tt.want)
+		}
+	}
+}
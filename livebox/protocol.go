@@ -1,6 +1,15 @@
 package livebox
 
-// Protocol used for port forwarding.
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Protocol is an IP protocol used by port forwarding, pinhole and firewall rules.
+// Well-known protocols are represented by their name (e.g. "tcp"), others by their
+// IP protocol number in decimal (e.g. "132" for SCTP).
 type Protocol string
 
 // List of supported protocols:
@@ -9,30 +18,128 @@ const (
 	ProtocolTCP     Protocol = "tcp"
 	ProtocolUDP     Protocol = "udp"
 	ProtocolTCPUDP  Protocol = "tcp/udp"
+	ProtocolICMP    Protocol = "icmp"
+	ProtocolICMPv6  Protocol = "icmpv6"
+	ProtocolGRE     Protocol = "gre"
+	ProtocolESP     Protocol = "esp"
+	ProtocolAH      Protocol = "ah"
 )
 
+// protocolNumbers maps the protocols having a name to their representation in the Livebox API.
+var protocolNumbers = map[Protocol]string{
+	ProtocolICMP:   "1",
+	ProtocolTCP:    "6",
+	ProtocolUDP:    "17",
+	ProtocolTCPUDP: "6,17",
+	ProtocolGRE:    "47",
+	ProtocolESP:    "50",
+	ProtocolAH:     "51",
+	ProtocolICMPv6: "58",
+}
+
+// protocolNames is the reverse of protocolNumbers.
+var protocolNames = func() map[string]Protocol {
+	names := make(map[string]Protocol, len(protocolNumbers))
+	for p, n := range protocolNumbers {
+		names[n] = p
+	}
+
+	return names
+}()
+
+// ParseProtocol parses either the name of a protocol (e.g. "tcp", case-insensitively)
+// or an IP protocol number (e.g. "6" or "132"). Protocols having a name are always returned
+// as such, so ParseProtocol("6") returns ProtocolTCP.
+func ParseProtocol(s string) (Protocol, error) {
+	if p := Protocol(strings.ToLower(s)); protocolNumbers[p] != "" {
+		return p, nil
+	}
+
+	if p := parseProtocol(s); p != ProtocolUnknown {
+		return p, nil
+	}
+
+	return ProtocolUnknown, fmt.Errorf("invalid protocol %q; must be a protocol name or an IP protocol number between 0 and 255", s)
+}
+
+// HasPorts reports whether the protocol uses ports, meaning it is TCP, UDP or both.
+func (p Protocol) HasPorts() bool {
+	return p == ProtocolTCP || p == ProtocolUDP || p == ProtocolTCPUDP
+}
+
+// MarshalText implements encoding.TextMarshaler. Protocols are marshaled as returned by ParseProtocol.
+func (p Protocol) MarshalText() ([]byte, error) {
+	if p.intString() == "" {
+		return nil, fmt.Errorf("invalid protocol %q", string(p))
+	}
+
+	return []byte(p), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the same values as ParseProtocol.
+func (p *Protocol) UnmarshalText(text []byte) error {
+	parsed, err := ParseProtocol(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+	return nil
+}
+
+// intString returns the representation of the protocol in the Livebox API,
+// or an empty string if the protocol is not valid.
 func (p Protocol) intString() string {
-	switch p {
-	case ProtocolTCP:
-		return "6"
-	case ProtocolUDP:
-		return "17"
-	case ProtocolTCPUDP:
-		return "6,17"
-	default:
-		return ""
+	if n, ok := protocolNumbers[p]; ok {
+		return n
+	}
+
+	if p != ProtocolUnknown && parseProtocol(string(p)) == p {
+		return string(p)
 	}
+
+	return ""
 }
 
+// overlaps reports whether traffic using protocol p would also match protocol o.
+func (p Protocol) overlaps(o Protocol) bool {
+	if p == o {
+		return true
+	}
+
+	return p == ProtocolTCPUDP && (o == ProtocolTCP || o == ProtocolUDP) ||
+		o == ProtocolTCPUDP && (p == ProtocolTCP || p == ProtocolUDP)
+}
+
+// parseProtocol parses the representation of a protocol in the Livebox API, which is either an IP protocol
+// number or the "6,17" list for TCP and UDP. Numbers without a name are kept as is, so no information is lost.
+// It returns ProtocolUnknown if p is not a valid representation.
 func parseProtocol(p string) Protocol {
-	switch p {
-	case "6":
-		return ProtocolTCP
-	case "17":
-		return ProtocolUDP
-	case "6,17":
-		return ProtocolTCPUDP
-	default:
+	var numbers []uint64
+	for _, n := range strings.Split(p, ",") {
+		v, err := strconv.ParseUint(strings.TrimSpace(n), 10, 8)
+		if err != nil {
+			return ProtocolUnknown
+		}
+
+		numbers = append(numbers, v)
+	}
+
+	// The only list of protocols supported by the Livebox is TCP and UDP, which may be written in any order.
+	slices.Sort(numbers)
+
+	parts := make([]string, 0, len(numbers))
+	for _, v := range numbers {
+		parts = append(parts, strconv.FormatUint(v, 10))
+	}
+
+	canonical := strings.Join(parts, ",")
+	if name, ok := protocolNames[canonical]; ok {
+		return name
+	}
+	if len(numbers) > 1 {
 		return ProtocolUnknown
 	}
+
+	return Protocol(canonical)
 }
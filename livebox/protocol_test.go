@@ -0,0 +1,120 @@
+package livebox
+
+import (
+	"testing"
+)
+
+func TestParseProtocol(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Protocol
+		wantErr bool
+	}{
+		{in: "tcp", want: ProtocolTCP},
+		{in: "TCP", want: ProtocolTCP},
+		{in: "tcp/udp", want: ProtocolTCPUDP},
+		{in: "ICMPv6", want: ProtocolICMPv6},
+		{in: "esp", want: ProtocolESP},
+		{in: "6", want: ProtocolTCP},
+		{in: "47", want: ProtocolGRE},
+		{in: "6,17", want: ProtocolTCPUDP},
+		{in: "132", want: Protocol("132")},
+		{in: "0", want: Protocol("0")},
+		{in: "", wantErr: true},
+		{in: "sctp", wantErr: true},
+		{in: "unknown", wantErr: true},
+		{in: "256", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "6,47", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseProtocol(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseProtocol(%q): got error %v, want error: %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			if got != ProtocolUnknown {
+				t.Errorf("ParseProtocol(%q) = %q on error; want %q", tt.in, got, ProtocolUnknown)
+			}
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseProtocol(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseProtocolFromAPI(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Protocol
+	}{
+		{in: "1", want: ProtocolICMP},
+		{in: "6", want: ProtocolTCP},
+		{in: "17", want: ProtocolUDP},
+		{in: "6,17", want: ProtocolTCPUDP},
+		{in: "17,6", want: ProtocolTCPUDP},
+		{in: " 6 , 17 ", want: ProtocolTCPUDP},
+		{in: "58", want: ProtocolICMPv6},
+		{in: "132", want: Protocol("132")},
+		{in: "255", want: Protocol("255")},
+		{in: "6,47", want: ProtocolUnknown},
+		{in: "256", want: ProtocolUnknown},
+		{in: "tcp", want: ProtocolUnknown},
+		{in: "", want: ProtocolUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := parseProtocol(tt.in); got != tt.want {
+			t.Errorf("parseProtocol(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestProtocolTextRoundTrip(t *testing.T) {
+	protocols := []Protocol{
+		ProtocolTCP,
+		ProtocolUDP,
+		ProtocolTCPUDP,
+		ProtocolICMP,
+		ProtocolICMPv6,
+		ProtocolGRE,
+		ProtocolESP,
+		ProtocolAH,
+		Protocol("132"),
+	}
+
+	for _, p := range protocols {
+		text, err := p.MarshalText()
+		if err != nil {
+			t.Errorf("MarshalText of %q: %v", p, err)
+			continue
+		}
+
+		var got Protocol
+		if err = got.UnmarshalText(text); err != nil {
+			t.Errorf("UnmarshalText(%q): %v", text, err)
+			continue
+		}
+		if got != p {
+			t.Errorf("round trip of %q got %q", p, got)
+		}
+
+		if parsed := parseProtocol(p.intString()); parsed != p {
+			t.Errorf("parseProtocol(%q) = %q; want %q", p.intString(), parsed, p)
+		}
+	}
+
+	for _, p := range []Protocol{ProtocolUnknown, Protocol("sctp"), Protocol("")} {
+		if _, err := p.MarshalText(); err == nil {
+			t.Errorf("MarshalText of %q: got no error", p)
+		}
+	}
+
+	var p Protocol
+	if err := p.UnmarshalText([]byte("sctp")); err == nil {
+		t.Errorf("UnmarshalText(%q): got no error", "sctp")
+	}
+}
@@ -2,7 +2,9 @@ package livebox
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -15,21 +17,39 @@ type apiRequest struct {
 
 type apiResponse struct {
 	Status json.RawMessage `json:"status"`
-	Errors json.RawMessage `json:"errors"`
+	Errors []APIError      `json:"errors"`
 }
 
-func (c *Client) doReq(r *apiRequest) (json.RawMessage, error) {
+// doReq sends the given request to the Livebox API and returns its status.
+// If the session has expired, it logs in again and retries the request once.
+// Errors reported by the API are returned as *APIError.
+func (c *Client) doReq(ctx context.Context, r *apiRequest) (json.RawMessage, error) {
+	token := c.currentToken()
+
+	status, err := c.doReqOnce(ctx, r, token)
+	if !errors.Is(err, ErrUnauthorized) {
+		return status, err
+	}
+
+	if err = c.relogin(ctx, token); err != nil {
+		return nil, fmt.Errorf("renew session: %w", err)
+	}
+
+	return c.doReqOnce(ctx, r, c.currentToken())
+}
+
+func (c *Client) doReqOnce(ctx context.Context, r *apiRequest, token string) (json.RawMessage, error) {
 	b, err := json.Marshal(r)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.host+"/ws", bytes.NewReader(b))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.currentHost()+"/ws", bytes.NewReader(b))
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "X-Sah "+c.token)
+	req.Header.Set("Authorization", "X-Sah "+token)
 	req.Header.Set("Content-Type", "application/x-sah-ws-4-call+json")
 
 	resp, err := c.httpClient.Do(req)
@@ -38,25 +58,29 @@ func (c *Client) doReq(r *apiRequest) (json.RawMessage, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("unexpected status %q: %w", resp.Status, ErrUnauthorized)
+	}
+
 	var apiResp apiResponse
 	if err = json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
 		return nil, err
 	}
 
 	if len(apiResp.Errors) > 0 {
-		return nil, fmt.Errorf("api error: %s", apiResp.Errors)
+		return nil, apiErrors(apiResp.Errors)
 	}
 
 	return apiResp.Status, nil
 }
 
-func (c *Client) doAuthReq(r *apiRequest) (*http.Response, error) {
+func (c *Client) doAuthReq(ctx context.Context, r *apiRequest) (*http.Response, error) {
 	b, err := json.Marshal(r)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.host+"/ws", bytes.NewReader(b))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.currentHost()+"/ws", bytes.NewReader(b))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,171 @@
+package livebox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeLivebox is a minimal Livebox API serving new sessions and answering authenticated
+// requests with the given handler.
+type fakeLivebox struct {
+	logins atomic.Int32
+	calls  atomic.Int32
+	// handle answers the given authenticated request made with the given token.
+	handle func(w http.ResponseWriter, token string, req *apiRequest)
+}
+
+func (f *fakeLivebox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if auth == "X-Sah-Login" {
+		n := f.logins.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"contextID": fmt.Sprintf("token-%d", n)},
+		})
+		return
+	}
+
+	var req apiRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.calls.Add(1)
+	f.handle(w, strings.TrimPrefix(auth, "X-Sah "), &req)
+}
+
+func newTestClient(t *testing.T, f *fakeLivebox) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(context.Background(), srv.URL, "password")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	return c
+}
+
+func writeStatus(w http.ResponseWriter) {
+	_, _ = w.Write([]byte(`{"status":true}`))
+}
+
+func testRequest() *apiRequest {
+	return &apiRequest{Service: "Test", Method: "get", Parameters: map[string]any{}}
+}
+
+func TestDoReqReloginOnExpiredSession(t *testing.T) {
+	tests := map[string]func(w http.ResponseWriter){
+		"http 401": func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusUnauthorized)
+		},
+		"permission denied": func(w http.ResponseWriter) {
+			_, _ = w.Write([]byte(`{"status":null,"errors":[{"error":13,"description":"Permission denied"}]}`))
+		},
+	}
+
+	for name, expired := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &fakeLivebox{}
+			f.handle = func(w http.ResponseWriter, token string, _ *apiRequest) {
+				if token == "token-1" {
+					expired(w)
+					return
+				}
+				writeStatus(w)
+			}
+
+			c := newTestClient(t, f)
+
+			if _, err := c.doReq(context.Background(), testRequest()); err != nil {
+				t.Fatalf("doReq: %v", err)
+			}
+
+			if got := f.logins.Load(); got != 2 {
+				t.Errorf("got %d logins, want 2", got)
+			}
+			if got := f.calls.Load(); got != 2 {
+				t.Errorf("got %d calls, want 2", got)
+			}
+			if got := c.currentToken(); got != "token-2" {
+				t.Errorf("got token %q, want %q", got, "token-2")
+			}
+		})
+	}
+}
+
+func TestDoReqDoesNotLoopOnUnauthorized(t *testing.T) {
+	f := &fakeLivebox{}
+	f.handle = func(w http.ResponseWriter, _ string, _ *apiRequest) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	c := newTestClient(t, f)
+
+	_, err := c.doReq(context.Background(), testRequest())
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("got error %v, want ErrUnauthorized", err)
+	}
+
+	if got := f.logins.Load(); got != 2 {
+		t.Errorf("got %d logins, want 2", got)
+	}
+	if got := f.calls.Load(); got != 2 {
+		t.Errorf("got %d calls, want 2", got)
+	}
+}
+
+func TestDoReqConcurrentReloginOnce(t *testing.T) {
+	const callers = 2
+
+	// Hold the responses to the expired token until every caller sent its request,
+	// so that they all fail with the same expired token.
+	var arrived sync.WaitGroup
+	arrived.Add(callers)
+
+	f := &fakeLivebox{}
+	f.handle = func(w http.ResponseWriter, token string, _ *apiRequest) {
+		if token == "token-1" {
+			arrived.Done()
+			arrived.Wait()
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeStatus(w)
+	}
+
+	c := newTestClient(t, f)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.doReq(context.Background(), testRequest())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("doReq: %v", err)
+		}
+	}
+
+	if got := f.logins.Load(); got != 2 {
+		t.Errorf("got %d logins, want 2", got)
+	}
+}